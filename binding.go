@@ -3,20 +3,34 @@ package modules
 import (
 	"errors"
 	"fmt"
+	"os"
 	"reflect"
 	"sync"
 
+	"github.com/go-modules/modules/inject"
+	"github.com/go-modules/modules/inject/dotenv"
+	"github.com/go-modules/modules/inject/env"
 	"github.com/go-modules/modules/tags"
+	"github.com/go-modules/modules/validate"
 )
 
 // newBinding returns a new binding configured with b
 func newBinding(binder *Binder) *binding {
+	injectors := make(map[string]inject.Injector, len(binder.injectors))
+	for k, v := range binder.injectors {
+		injectors[k] = v
+	}
 	return &binding{
 		binder,
-		fields{m: make(map[bindKey]reflect.Value)},
+		injectors,
+		os.LookupEnv,
+		fields{m: make(map[bindKey]bound)},
 		gates{m: make(map[bindKey]gate)},
 		newGate(),
 		make(chan error),
+		closers{},
+		sync.Map{},
+		nil,
 	}
 }
 
@@ -24,6 +38,10 @@ func newBinding(binder *Binder) *binding {
 type binding struct {
 	// Configuration.
 	*Binder
+	// Injectors by tag key, copied from the Binder and possibly extended for this binding.
+	injectors map[string]inject.Injector
+	// Looks up environment variables for interpolation of tag values.
+	lookupEnv func(string) (string, bool)
 	// The bound fields.
 	fields
 	// The provider/injector gates.
@@ -32,6 +50,12 @@ type binding struct {
 	cancel gate
 	// Cancelled injection goroutines send errors here
 	errors chan error
+	// Resources opened by injectors during this binding.
+	opened closers
+	// Shared by injectors during this binding.
+	cache sync.Map
+	// Values provided with the 'reload' option.
+	reloadables []*reloadable
 }
 
 // Inject injectss the value bound to bindName into value.
@@ -40,23 +64,91 @@ func (b *binding) inject(bindName string, value reflect.Value) {
 	// Wait to inject this field after it has been provided, or binding cancelled.
 	select {
 	case <-b.cancel:
-		b.logf("nothing bound to %s\n", key.String())
 	case <-b.gates.get(key):
-		if bound, ok := b.fields.get(key); ok {
-			value.Set(bound)
-			b.logf("%v <- %s\n", bound, key.String())
-		} else {
-			b.logf("nothing bound to %s\n", key.String())
+	}
+	// The field may have been provided even if cancelled, since both channels may be ready.
+	if bound, ok := b.fields.get(key); ok {
+		if bound.newValue != nil {
+			bound.value = bound.newValue()
 		}
+		value.Set(bound.value)
+		b.logf("%s <- %s\n", format(bound.value, bound.secret), key.String())
+	} else {
+		b.logf("nothing bound to %s\n", key.String())
 	}
 }
 
-// provide binds value to bindName.
-// Each recognized tag key's inject.Injector will be executed until one sets the value.
-func (b *binding) provide(bindName string, singleton bool, tag tags.StructTag, value reflect.Value) error {
-	key := bindKey{value.Type(), bindName}
+// provide binds value to the name in ctx.
+// Each recognized tag key's inject.Injector will be executed with its interpolated tag value until one sets the value,
+// and then the value is checked against any 'validate' tag rules. Values with the 'required' option must be set.
+// The value is bound even if an error is returned.
+// The source describes where value came from if no inject.Injector sets it.
+func (b *binding) provide(ctx inject.InjectionContext, value reflect.Value, source string) error {
+	key := bindKey{value.Type(), ctx.Name}
+	singleton := ctx.Options.Contains("singleton")
+	// Resources opened for reloadable values are closed when the values are replaced.
+	var handles *closers
+	if ctx.Options.Contains("reload") {
+		handles = &closers{}
+		b.opened.track(handles)
+		ctx.OnClose = handles.track
+	}
+	tagKey, sourceTagValue, tried, err := b.injectTags(ctx, value)
+	if tagKey != "" {
+		source = tagKey
+	}
+
+	// Required values must be set by an injector, prior to binding, or by Provide().
+	if err == nil && source == SourceNone && ctx.Options.Contains("required") {
+		err = &MissingError{Key: key.String(), Field: typeName(ctx.ModuleType) + "." + ctx.Field.Name, Tried: tried}
+	}
+
+	// Validate the value, unless it failed to be set.
+	if err == nil {
+		err = validateTag(ctx, key, value)
+	}
+
+	// Secrets are redacted from logs and hooks.
+	redact := ctx.Options.Contains("secret") || b.isSecretSource(source) || isSecret(value)
+
+	// The value to bind.
+	var toBind reflect.Value
+	if singleton && value.Kind() == reflect.Func && !value.IsNil() {
+		// Inject a singleton by wrapping the provided function.
+		toBind = asSingleton(value)
+		b.logf("singleton(%s) -> %s -> %s\n", format(value, redact), format(toBind, redact), key)
+	} else {
+		// Inject the value that was provided, as is.
+		toBind = value
+		b.logf("%s -> %s\n", format(value, redact), key)
+	}
+
+	// Provide this field.
+	b.fields.bind(key, bound{value: toBind, secret: redact})
+	b.callHooks(ctx, value, source, sourceTagValue, redact)
+
+	// Reloadable values may also be injected as getters and channels.
+	if ctx.Options.Contains("reload") {
+		b.provideReloadable(ctx, key, value, redact, handles)
+	}
+
+	// Broadcast to waiting injectors.
+	close(b.gates.get(key))
+	return err
+}
+
+// isSecretSource returns true if source is the tag key of an inject.SecretInjector.
+func (b *binding) isSecretSource(source string) bool {
+	_, ok := b.injectors[source].(inject.SecretInjector)
+	return ok
+}
+
+// injectTags executes each recognized tag key's inject.Injector with its interpolated tag value until one sets value.
+// Returns the tag key and value which set value, if any, and the sources tried.
+func (b *binding) injectTags(ctx inject.InjectionContext, value reflect.Value) (source, sourceTagValue string, tried []Source, err error) {
+	key := bindKey{value.Type(), ctx.Name}
 	// Range over tag fields until a known tag key's inject.Injector sets the value.
-	tag.ForEach(tags.Handler(func(tagKey, v string) (bool, error) {
+	err = ctx.Tag().ForEach(tags.Handler(func(tagKey, v string) (bool, error) {
 		if tagKey == "provide" {
 			return false, nil
 		}
@@ -64,11 +156,17 @@ func (b *binding) provide(bindName string, singleton bool, tag tags.StructTag, v
 			return false, errors.New(fmt.Sprintf("failed to parse tags for value %s ;a module field tagged with 'provide' cannot also be tagged with 'inject'", key))
 		}
 		if injector, ok := b.injectors[tagKey]; ok {
-			if ok, err := injector.Inject(value, v); err != nil {
+			v, err := b.interpolate(v)
+			if err != nil {
+				return false, &AnnotatedError{msg: fmt.Sprintf("failed to provide value for %s from tag key %s", key, tagKey), cause: err}
+			}
+			tried = append(tried, Source{tagKey, inject.Describe(injector, ctx, v)})
+			if ok, err := inject.InjectWithContext(injector, ctx, value, v); err != nil {
 				// Failed to set value.
 				return false, &AnnotatedError{msg: fmt.Sprintf("failed to provide value for %s from tag key %s", key, tagKey), cause: err}
 			} else if ok {
 				// Value has been set. Done.
+				source, sourceTagValue = tagKey, v
 				return true, nil
 			} else {
 				// Value has not been set. Continue.
@@ -79,43 +177,113 @@ func (b *binding) provide(bindName string, singleton bool, tag tags.StructTag, v
 			return false, nil
 		}
 	}))
+	return source, sourceTagValue, tried, err
+}
 
-	// The value to bind.
-	var toBind reflect.Value
-	if singleton && value.Kind() == reflect.Func && !value.IsNil() {
-		// Inject a singleton by wrapping the provided function.
-		toBind = asSingleton(value)
-		b.logf("singleton(%v) -> %v -> %s\n", value, toBind, key)
-	} else {
-		// Inject the value that was provided, as is.
-		toBind = value
-		b.logf("%v -> %s\n", value, key)
+// validateTag checks value against the field's 'validate' tag rules, if any.
+func validateTag(ctx inject.InjectionContext, key bindKey, value reflect.Value) error {
+	if rules, ok := ctx.Tag().Get("validate"); ok {
+		if violations := validate.Validate(value, rules); len(violations) > 0 {
+			return &ValidationError{Key: key.String(), Field: typeName(ctx.ModuleType) + "." + ctx.Field.Name, Violations: violations}
+		}
 	}
+	return nil
+}
 
-	// Provide this field.
-	b.fields.bind(key, toBind)
+// callHooks calls the Binder's ProvideHooks, if any, with the provided value.
+func (b *binding) callHooks(ctx inject.InjectionContext, value reflect.Value, source, tagValue string, redact bool) {
+	if len(b.hooks) > 0 {
+		provided := newProvidedValue(ctx, value, source, tagValue, redact)
+		for _, hook := range b.hooks {
+			hook(provided)
+		}
+	}
+}
 
-	// Broadcast to waiting injectors.
-	close(b.gates.get(key))
+// loadDotEnv loads the .env files configured by d, registers the 'dotenv' injector, and wraps the 'env' injector's
+// lookup to use them.
+func (b *binding) loadDotEnv(d DotEnv) error {
+	paths := d.Paths
+	if len(paths) == 0 {
+		paths = []string{".env"}
+	}
+	existing := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			existing = append(existing, path)
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+	vars, err := dotenv.Load(existing...)
+	if err != nil {
+		return err
+	}
+	lookup := dotenv.FirstOf(os.LookupEnv, vars.Lookup)
+	if d.Override {
+		lookup = dotenv.FirstOf(vars.Lookup, os.LookupEnv)
+	}
+	if !b.disabled["dotenv"] {
+		b.injectors["dotenv"] = dotenv.New(vars)
+	}
+	if injector, ok := b.injectors["env"]; ok {
+		wrapped, ok := env.WithLookup(injector, func(configured env.Lookup) env.Lookup {
+			if d.Override {
+				return dotenv.FirstOf(vars.Lookup, configured)
+			}
+			return dotenv.FirstOf(configured, vars.Lookup)
+		})
+		if ok {
+			b.injectors["env"] = wrapped
+		} else {
+			b.logf(".env variables are not available to the 'env' tag key's custom injector\n")
+		}
+	}
+	b.lookupEnv = lookup
+	b.logf("loaded .env files: %v\n", existing)
 	return nil
 }
 
+// A bound value, and whether it is secret.
+type bound struct {
+	value  reflect.Value
+	secret bool
+	// If not nil, returns a new value for each injection in place of value.
+	newValue func() reflect.Value
+	// Whether this is another form of a value bound with the same name (e.g. a reload getter), which is excluded
+	// from lookups by name.
+	derived bool
+}
+
 // A fields instance holds bound field values mapped by bindKeys.
 type fields struct {
 	sync.RWMutex
-	m map[bindKey]reflect.Value
+	m map[bindKey]bound
 }
 
 // get retrieves the value bound to key.
-func (f *fields) get(key bindKey) (reflect.Value, bool) {
+func (f *fields) get(key bindKey) (bound, bool) {
 	f.RLock()
 	value, ok := f.m[key]
 	f.RUnlock()
 	return value, ok
 }
 
+// getByName retrieves the values bound to name, of any type, except derived values.
+func (f *fields) getByName(name string) map[bindKey]bound {
+	f.RLock()
+	defer f.RUnlock()
+	matches := make(map[bindKey]bound)
+	for key, value := range f.m {
+		if key.name == name && !value.derived {
+			matches[key] = value
+		}
+	}
+	return matches
+}
+
 // bind binds value to key.
-func (f *fields) bind(key bindKey, value reflect.Value) {
+func (f *fields) bind(key bindKey, value bound) {
 	f.Lock()
 	f.m[key] = value
 	f.Unlock()
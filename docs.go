@@ -0,0 +1,193 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/go-modules/modules/inject"
+	"github.com/go-modules/modules/inject/gnuflag"
+	"github.com/go-modules/modules/secret"
+	"github.com/go-modules/modules/tags"
+)
+
+// An Input describes a provided field which may be set by a Binder's injectors.
+type Input struct {
+	// The module type declaring the field.
+	Module string `json:"module"`
+	// The field name.
+	Field string `json:"field"`
+	// The name the field is provided as.
+	Name string `json:"name"`
+	// The field type.
+	Type string `json:"type"`
+	// The sources which may set the field, in order of precedence, as described by their injectors (see
+	// inject.Describer). Excludes 'literal' tags.
+	Sources []Source `json:"sources,omitempty"`
+	// The value of the 'literal' tag, if present. Redacted for secret fields.
+	Default *string `json:"default,omitempty"`
+	// The value of the 'usage' tag, or else the 'doc' tag.
+	Usage string `json:"usage,omitempty"`
+	// Whether the 'provide' tag has the 'required' option.
+	Required bool `json:"required,omitempty"`
+}
+
+// A Source is a tag key and value which may set an Input.
+type Source struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// String formats s as it would be supplied, e.g. "-port" for a flag, or "env PORT" for an environment variable.
+func (s Source) String() string {
+	switch s.Key {
+	case "flag":
+		return "-" + s.Value
+	case "gnuflag":
+		name, short, err := gnuflag.ParseTag(s.Value)
+		if err != nil {
+			return s.Key + " " + s.Value
+		}
+		if short != 0 {
+			return fmt.Sprintf("--%s, -%c", name, short)
+		}
+		return "--" + name
+	case "arg":
+		return "argument " + s.Value
+	}
+	return s.Key + " " + s.Value
+}
+
+// Inputs describes the provided fields of modules which may be set by b's injectors, in module and field order.
+func (b *Binder) Inputs(modules ...interface{}) Inputs {
+	inputs := make(Inputs, 0)
+	providedFields(modules, func(moduleType reflect.Type, field reflect.StructField, tag tags.StructTag) error {
+		provide, _ := tag.Get("provide")
+		name, options := tags.ParseTag(provide)
+		input := Input{
+			Module:   typeName(moduleType),
+			Field:    field.Name,
+			Name:     name,
+			Type:     field.Type.String(),
+			Required: options.Contains("required"),
+		}
+		configurable := false
+		tag.ForEach(func(k, v string) (bool, error) {
+			injector, ok := b.injectors[k]
+			if !ok {
+				return false, nil
+			}
+			configurable = true
+			if k == "literal" {
+				v := v
+				if isSecretField(field, options) {
+					v = secret.Redacted
+				}
+				input.Default = &v
+			} else {
+				ctx := inject.InjectionContext{Field: field, ModuleType: moduleType, Name: name, Options: options}
+				input.Sources = append(input.Sources, Source{k, inject.Describe(injector, ctx, v)})
+			}
+			return false, nil
+		})
+		if !configurable {
+			return nil
+		}
+		if usage, ok := tag.Get("usage"); ok {
+			input.Usage = usage
+		} else {
+			input.Usage, _ = tag.Get("doc")
+		}
+		inputs = append(inputs, input)
+		return nil
+	})
+	return inputs
+}
+
+// typeName returns the name of a module type, or its full description if unnamed.
+func typeName(t reflect.Type) string {
+	if t.Name() != "" {
+		return t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:] + "." + t.Name()
+	}
+	return t.String()
+}
+
+// Inputs describes the configurable inputs of a set of modules.
+type Inputs []Input
+
+// WriteJSON writes the inputs as a JSON array.
+func (in Inputs) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(in)
+}
+
+// WriteMarkdown writes the inputs as a Markdown table.
+func (in Inputs) WriteMarkdown(w io.Writer) error {
+	rows := [][]string{
+		{"Name", "Type", "Sources", "Default", "Required", "Description", "Module"},
+		{"---", "---", "---", "---", "---", "---", "---"},
+	}
+	for _, input := range in {
+		sources := make([]string, len(input.Sources))
+		for i, source := range input.Sources {
+			sources[i] = "`" + source.String() + "`"
+		}
+		def := ""
+		if input.Default != nil {
+			def = "`" + *input.Default + "`"
+		}
+		required := ""
+		if input.Required {
+			required = "yes"
+		}
+		rows = append(rows, []string{input.Name, "`" + input.Type + "`", strings.Join(sources, ", "), def, required, input.Usage, input.Module})
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = strings.Replace(strings.Replace(cell, "|", "\\|", -1), "\n", " ", -1)
+		}
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(row, " | ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteHelp writes the inputs as plain text, grouped by module, suitable for --help output.
+func (in Inputs) WriteHelp(w io.Writer) error {
+	module := ""
+	for _, input := range in {
+		if input.Module != module {
+			module = input.Module
+			if _, err := fmt.Fprintf(w, "%s:\n", module); err != nil {
+				return err
+			}
+		}
+		sources := make([]string, len(input.Sources))
+		for i, source := range input.Sources {
+			sources[i] = source.String()
+		}
+		line := "  " + input.Name + " (" + input.Type + ")"
+		if len(sources) > 0 {
+			line += ": " + strings.Join(sources, ", ")
+		}
+		if input.Default != nil {
+			line += fmt.Sprintf(" (default %q)", *input.Default)
+		}
+		if input.Required {
+			line += " (required)"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+		if input.Usage != "" {
+			if _, err := fmt.Fprintf(w, "    \t%s\n", input.Usage); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
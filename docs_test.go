@@ -0,0 +1,104 @@
+package modules
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-modules/modules/inject/env"
+	"github.com/go-modules/modules/secret"
+)
+
+type serverModule struct {
+	Port    int    `provide:"port" flag:"port" env:"PORT" literal:"80" usage:"the port to listen on"`
+	Host    string `provide:"host,required" gnuflag:"host,short=h" doc:"the host | name"`
+	Handler func() `provide:"handler"`
+	Name    string `inject:"name"`
+}
+
+func TestInputs(t *testing.T) {
+	inputs := NewBinder().Inputs(&serverModule{})
+	def := "80"
+	expected := Inputs{
+		{
+			Module:  "modules.serverModule",
+			Field:   "Port",
+			Name:    "port",
+			Type:    "int",
+			Sources: []Source{{"flag", "port"}, {"env", "PORT"}},
+			Default: &def,
+			Usage:   "the port to listen on",
+		},
+	}
+	if !reflect.DeepEqual(expected, inputs) {
+		t.Fatalf("expected %+v got %+v", expected, inputs)
+	}
+
+	// Registered tag keys are included.
+	inputs = NewBinder(GNUFlags{}).Inputs(&serverModule{})
+	if len(inputs) != 2 {
+		t.Fatalf("expected 2 inputs got %d", len(inputs))
+	}
+
+	var buf bytes.Buffer
+	if err := inputs.WriteHelp(&buf); err != nil {
+		t.Fatal(err)
+	}
+	assertString(t, `modules.serverModule:
+  port (int): -port, env PORT (default "80")
+    	the port to listen on
+  host (string): --host, -h (required)
+    	the host | name
+`, buf.String())
+
+	buf.Reset()
+	if err := inputs.WriteMarkdown(&buf); err != nil {
+		t.Fatal(err)
+	}
+	assertString(t, "| Name | Type | Sources | Default | Required | Description | Module |\n"+
+		"| --- | --- | --- | --- | --- | --- | --- |\n"+
+		"| port | `int` | `-port`, `env PORT` | `80` |  | the port to listen on | modules.serverModule |\n"+
+		"| host | `string` | `--host, -h` |  | yes | the host \\| name | modules.serverModule |\n", buf.String())
+
+	buf.Reset()
+	if err := inputs.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Inputs
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(inputs, decoded) {
+		t.Errorf("expected %+v got %+v", inputs, decoded)
+	}
+	// Defaults of secret fields are redacted.
+	inputs = NewBinder().Inputs(&struct {
+		Password    string               `provide:"dbPassword,secret" env:"DB_PASSWORD" literal:"hunter2"`
+		APIKey      secret.String        `provide:"apiKey" env:"API_KEY" literal:"key"`
+		Certificate secret.Secret[[]int] `provide:"cert" env:"CERT"`
+	}{})
+	for _, input := range inputs[:2] {
+		if input.Default == nil || *input.Default != secret.Redacted {
+			t.Errorf("expected redacted default for %s", input.Name)
+		}
+	}
+	if inputs[2].Default != nil {
+		t.Errorf("expected no default for %s", inputs[2].Name)
+	}
+
+	// Sources name the variables looked up by configured injectors.
+	inputs = NewBinder(Injectors{"env": env.New(env.Prefix("MYAPP_"), env.AutoName(env.ScreamingSnake))}).Inputs(&struct {
+		DBHost string `provide:"dbHost" env:""`
+		Port   int    `provide:"port" env:"PORT"`
+	}{})
+	if len(inputs) != 2 {
+		t.Fatalf("expected 2 inputs got %d", len(inputs))
+	}
+	if !reflect.DeepEqual([]Source{{"env", "MYAPP_DB_HOST"}}, inputs[0].Sources) {
+		t.Errorf("unexpected sources %+v", inputs[0].Sources)
+	}
+	if !reflect.DeepEqual([]Source{{"env", "MYAPP_PORT"}}, inputs[1].Sources) {
+		t.Errorf("unexpected sources %+v", inputs[1].Sources)
+	}
+}
@@ -3,6 +3,7 @@ package modules
 import (
 	"bytes"
 	"fmt"
+	"strings"
 )
 
 // An AnnotatedError holds a message and wraps another error.
@@ -15,12 +16,38 @@ func (e *AnnotatedError) Error() string {
 	return e.msg + " caused by: " + e.cause.Error()
 }
 
+// Unwrap returns the cause of e, for errors.Is and errors.As.
+func (e *AnnotatedError) Unwrap() error {
+	return e.cause
+}
+
 // A BindingError indicates failure during binding.
 // Holds one or more errors which prevented binding.
 type BindingError struct {
 	errs []error
 }
 
+// Errors returns the errors which prevented binding.
+func (e *BindingError) Errors() []error {
+	return e.errs
+}
+
+// Unwrap returns the errors which prevented binding, for errors.Is and errors.As, e.g. to find a *file.ChecksumError.
+func (e *BindingError) Unwrap() []error {
+	return e.errs
+}
+
+// Missing returns the errors for required values which were not set.
+func (e *BindingError) Missing() []*MissingError {
+	missing := make([]*MissingError, 0)
+	for _, err := range e.errs {
+		if m, ok := err.(*MissingError); ok {
+			missing = append(missing, m)
+		}
+	}
+	return missing
+}
+
 func (e *BindingError) Error() string {
 	errMsg := bytes.NewBufferString("binding failed:")
 	for _, err := range e.errs {
@@ -28,3 +55,61 @@ func (e *BindingError) Error() string {
 	}
 	return errMsg.String()
 }
+
+// A ValidationError indicates that a provided value does not satisfy its 'validate' tag rules.
+type ValidationError struct {
+	// The bind key of the provided value, e.g. {int|port}.
+	Key string
+	// The module type and field name, e.g. main.ServerModule.Port.
+	Field string
+	// The rule violations.
+	Violations []error
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, err := range e.Violations {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("invalid value for %s from %s: %s", e.Key, e.Field, strings.Join(msgs, "; "))
+}
+
+// A MissingError indicates that a provided value with the 'required' option was not set.
+type MissingError struct {
+	// The bind key of the provided value, e.g. {string|dbHost}.
+	Key string
+	// The module type and field name, e.g. main.DataModule.DBHost.
+	Field string
+	// The tag keys and values which were tried, in order.
+	Tried []Source
+}
+
+func (e *MissingError) Error() string {
+	msg := fmt.Sprintf("missing required value for %s from %s", e.Key, e.Field)
+	if len(e.Tried) > 0 {
+		tried := make([]string, len(e.Tried))
+		for i, source := range e.Tried {
+			tried[i] = source.String()
+		}
+		msg += "; tried: " + strings.Join(tried, ", ")
+	}
+	return msg
+}
+
+// A CloseError indicates failure to close one or more resources opened while binding.
+type CloseError struct {
+	errs []error
+}
+
+// Errors returns the errors from closing resources.
+func (e *CloseError) Errors() []error {
+	return e.errs
+}
+
+func (e *CloseError) Error() string {
+	msgs := make([]string, len(e.errs))
+	for i, err := range e.errs {
+		msgs[i] = err.Error()
+	}
+	return "close failed: " + strings.Join(msgs, "; ")
+}
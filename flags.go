@@ -0,0 +1,54 @@
+package modules
+
+import (
+	"reflect"
+
+	"github.com/go-modules/modules/inject/arg"
+	"github.com/go-modules/modules/inject/gnuflag"
+	"github.com/go-modules/modules/tags"
+)
+
+// GNUFlags is a functional option which defines GNU style command line flags for the 'gnuflag' tagged provided
+// fields of bound modules, and parses arguments before each binding, so the Binder may be reused. Unknown flags and
+// invalid values fail binding.
+// The 'gnuflag' tag key is mapped to FlagSet, and the 'arg' tag key to an injector for its positional arguments.
+type GNUFlags struct {
+	// The FlagSet to define flags on and parse, defaulting to one which parses os.Args[1:].
+	// Positional arguments are available from FlagSet.Args() after binding.
+	FlagSet *gnuflag.FlagSet
+}
+
+func (g GNUFlags) configure(b *Binder) {
+	if g.FlagSet == nil {
+		g.FlagSet = gnuflag.NewFlagSet(nil)
+	}
+	b.gnuFlags = &g
+	b.injectors["gnuflag"] = g.FlagSet
+	b.injectors["arg"] = arg.New(g.FlagSet.Args)
+}
+
+// parse defines a flag for each 'gnuflag' tagged provided field of modules, and parses the arguments.
+func (g *GNUFlags) parse(modules []interface{}) error {
+	errs := make([]error, 0)
+	providedFields(modules, func(_ reflect.Type, field reflect.StructField, tag tags.StructTag) error {
+		if tagValue, ok := tag.Get("gnuflag"); ok {
+			if err := g.FlagSet.Define(tagValue, field.Type); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return nil
+	})
+	if len(errs) == 0 {
+		if err := g.FlagSet.Parse(); err != nil {
+			if parseErrs, ok := err.(gnuflag.Errors); ok {
+				errs = append(errs, parseErrs...)
+			} else {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return &BindingError{errs}
+	}
+	return nil
+}
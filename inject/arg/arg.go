@@ -0,0 +1,58 @@
+// Package arg provides an inject.Injector to set values from positional command line arguments.
+//
+// Tag values are argument indexes, e.g. arg:"0", or an index followed by "..." to set a slice from all remaining
+// arguments, e.g. arg:"1...". Argument strings are parsed by literal.Injector.
+package arg
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-modules/modules/inject"
+	"github.com/go-modules/modules/inject/literal"
+)
+
+// Injector is an inject.Injector for the positional arguments remaining after parsing flag.CommandLine.
+var Injector = New(flag.Args)
+
+// New returns an inject.Injector for the positional arguments returned by args, which is called for each injection
+// so that arguments may be parsed prior to binding, e.g. New(flagSet.Args).
+func New(args func() []string) inject.Injector {
+	return inject.InjectorFunc(func(value reflect.Value, tagValue string) (bool, error) {
+		return Inject(args(), value, tagValue)
+	})
+}
+
+// Inject sets value from the argument indexed by tagValue, or a slice value from the arguments starting at the index
+// if tagValue ends with "...".
+// Only sets value if the argument is present, otherwise passes by returning (false, nil).
+func Inject(args []string, value reflect.Value, tagValue string) (bool, error) {
+	rest := strings.HasSuffix(tagValue, "...")
+	index, err := strconv.Atoi(strings.TrimSuffix(tagValue, "..."))
+	if err != nil || index < 0 {
+		return false, fmt.Errorf("invalid argument index: %q", tagValue)
+	}
+	if index >= len(args) {
+		return false, nil
+	}
+	if !rest {
+		return literal.Injector.Inject(value, args[index])
+	}
+
+	if value.Kind() != reflect.Slice {
+		return false, errors.New("remaining arguments may only be injected into slices, not " + value.Type().String())
+	}
+	args = args[index:]
+	slice := reflect.MakeSlice(value.Type(), len(args), len(args))
+	for i, arg := range args {
+		if _, err := literal.Injector.Inject(slice.Index(i), arg); err != nil {
+			return false, fmt.Errorf("argument %d: %s", index+i, err)
+		}
+	}
+	value.Set(slice)
+	return true, nil
+}
@@ -0,0 +1,44 @@
+package arg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInject(t *testing.T) {
+	args := []string{"cmd", "1", "2", "3"}
+	for _, testCase := range []struct {
+		value    reflect.Value
+		tagValue string
+		expected interface{}
+	}{
+		{reflect.New(reflect.TypeOf("")).Elem(), "0", "cmd"},
+		{reflect.New(reflect.TypeOf(0)).Elem(), "2", 2},
+		{reflect.New(reflect.TypeOf([]int{})).Elem(), "1...", []int{1, 2, 3}},
+		{reflect.New(reflect.TypeOf([]string{})).Elem(), "0...", args},
+	} {
+		if ok, err := New(func() []string { return args }).Inject(testCase.value, testCase.tagValue); err != nil {
+			t.Error(err)
+		} else if !ok {
+			t.Errorf("%s: expected value to be set", testCase.tagValue)
+		} else if !reflect.DeepEqual(testCase.expected, testCase.value.Interface()) {
+			t.Errorf("%s: expected %v but got %v", testCase.tagValue, testCase.expected, testCase.value)
+		}
+	}
+
+	var s string
+	if ok, err := Inject(args, reflect.ValueOf(&s).Elem(), "4"); err != nil {
+		t.Error(err)
+	} else if ok {
+		t.Error("expected missing argument not to be set")
+	}
+	for _, tagValue := range []string{"x", "-1", "1..."} {
+		if _, err := Inject(args, reflect.ValueOf(&s).Elem(), tagValue); err == nil {
+			t.Errorf("%s: expected error", tagValue)
+		}
+	}
+	var ints []int
+	if _, err := Inject(args, reflect.ValueOf(&ints).Elem(), "0..."); err == nil {
+		t.Error("expected error parsing slice elements")
+	}
+}
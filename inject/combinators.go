@@ -0,0 +1,71 @@
+package inject
+
+import "reflect"
+
+// FirstOf returns an Injector which calls each of injectors in order with the same tag value, until one sets the value.
+func FirstOf(injectors ...Injector) Injector {
+	return FieldInjectorFunc(func(ctx InjectionContext, value reflect.Value, tagValue string) (bool, error) {
+		for _, injector := range injectors {
+			if ok, err := InjectWithContext(injector, ctx, value, tagValue); err != nil {
+				return false, err
+			} else if ok {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+// Chain returns an Injector which calls every one of injectors in order with the same tag value.
+// Values set by later injectors override those set by earlier ones. Reports the value as set if any injector set it.
+func Chain(injectors ...Injector) Injector {
+	return FieldInjectorFunc(func(ctx InjectionContext, value reflect.Value, tagValue string) (bool, error) {
+		set := false
+		for _, injector := range injectors {
+			if ok, err := InjectWithContext(injector, ctx, value, tagValue); err != nil {
+				return false, err
+			} else if ok {
+				set = true
+			}
+		}
+		return set, nil
+	})
+}
+
+// Transform returns an Injector which rewrites tag values with fn before passing them to injector.
+func Transform(injector Injector, fn func(string) string) Injector {
+	return FieldInjectorFunc(func(ctx InjectionContext, value reflect.Value, tagValue string) (bool, error) {
+		return InjectWithContext(injector, ctx, value, fn(tagValue))
+	})
+}
+
+// Fallback returns an Injector which calls fallback with fallbackTagValue when injector does not set the value.
+func Fallback(injector, fallback Injector, fallbackTagValue string) Injector {
+	return FieldInjectorFunc(func(ctx InjectionContext, value reflect.Value, tagValue string) (bool, error) {
+		if ok, err := InjectWithContext(injector, ctx, value, tagValue); err != nil || ok {
+			return ok, err
+		}
+		return InjectWithContext(fallback, ctx, value, fallbackTagValue)
+	})
+}
+
+// Required returns an Injector which returns a NotSetError when injector does not set the value.
+func Required(injector Injector) Injector {
+	return FieldInjectorFunc(func(ctx InjectionContext, value reflect.Value, tagValue string) (bool, error) {
+		if ok, err := InjectWithContext(injector, ctx, value, tagValue); err != nil {
+			return false, err
+		} else if !ok {
+			return false, &NotSetError{tagValue}
+		}
+		return true, nil
+	})
+}
+
+// A NotSetError indicates that a required value was not set.
+type NotSetError struct {
+	TagValue string
+}
+
+func (e *NotSetError) Error() string {
+	return "required value not set for tag value: " + e.TagValue
+}
@@ -0,0 +1,130 @@
+package inject
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stubInjector returns an Injector which sets string values from m, and records the tag values it was called with.
+func stubInjector(m map[string]string, calls *[]string) Injector {
+	return InjectorFunc(func(value reflect.Value, tagValue string) (bool, error) {
+		*calls = append(*calls, tagValue)
+		if s, ok := m[tagValue]; ok {
+			value.SetString(s)
+			return true, nil
+		}
+		return false, nil
+	})
+}
+
+func TestFirstOf(t *testing.T) {
+	var calls []string
+	injector := FirstOf(
+		stubInjector(map[string]string{}, &calls),
+		stubInjector(map[string]string{"key": "second"}, &calls),
+		stubInjector(map[string]string{"key": "third"}, &calls),
+	)
+	var s string
+	if ok, err := injector.Inject(reflect.ValueOf(&s).Elem(), "key"); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("expected value to be set")
+	}
+	if s != "second" {
+		t.Errorf("expected %q got %q", "second", s)
+	}
+	if len(calls) != 2 {
+		t.Errorf("expected 2 calls got %d", len(calls))
+	}
+
+	if ok, err := injector.Inject(reflect.ValueOf(&s).Elem(), "missing"); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Error("expected value not to be set")
+	}
+}
+
+func TestChain(t *testing.T) {
+	var calls []string
+	injector := Chain(
+		stubInjector(map[string]string{"key": "first"}, &calls),
+		stubInjector(map[string]string{}, &calls),
+		stubInjector(map[string]string{"key": "third"}, &calls),
+	)
+	var s string
+	if ok, err := injector.Inject(reflect.ValueOf(&s).Elem(), "key"); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("expected value to be set")
+	}
+	if s != "third" {
+		t.Errorf("expected %q got %q", "third", s)
+	}
+	if len(calls) != 3 {
+		t.Errorf("expected 3 calls got %d", len(calls))
+	}
+}
+
+func TestTransform(t *testing.T) {
+	var calls []string
+	injector := Transform(stubInjector(map[string]string{"DB_HOST": "host"}, &calls), func(s string) string {
+		return strings.ToUpper(strings.Replace(s, ".", "_", -1))
+	})
+	var s string
+	if ok, err := injector.Inject(reflect.ValueOf(&s).Elem(), "db.host"); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("expected value to be set")
+	}
+	if s != "host" {
+		t.Errorf("expected %q got %q", "host", s)
+	}
+}
+
+func TestFallback(t *testing.T) {
+	var calls []string
+	injector := Fallback(
+		stubInjector(map[string]string{"set": "primary"}, &calls),
+		stubInjector(map[string]string{"default": "fallback"}, &calls),
+		"default",
+	)
+	for _, testCase := range []struct {
+		tagValue string
+		expected string
+	}{
+		{"set", "primary"},
+		{"unset", "fallback"},
+	} {
+		var s string
+		if ok, err := injector.Inject(reflect.ValueOf(&s).Elem(), testCase.tagValue); err != nil {
+			t.Fatal(err)
+		} else if !ok {
+			t.Fatal("expected value to be set")
+		}
+		if s != testCase.expected {
+			t.Errorf("expected %q got %q", testCase.expected, s)
+		}
+	}
+}
+
+func TestRequired(t *testing.T) {
+	var calls []string
+	injector := Required(stubInjector(map[string]string{"key": "value"}, &calls))
+	var s string
+	if ok, err := injector.Inject(reflect.ValueOf(&s).Elem(), "key"); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("expected value to be set")
+	}
+
+	_, err := injector.Inject(reflect.ValueOf(&s).Elem(), "missing")
+	var notSet *NotSetError
+	if !errors.As(err, &notSet) {
+		t.Fatalf("expected NotSetError got %v", err)
+	}
+	if notSet.TagValue != "missing" {
+		t.Errorf("expected %q got %q", "missing", notSet.TagValue)
+	}
+}
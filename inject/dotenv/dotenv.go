@@ -0,0 +1,349 @@
+// Package dotenv provides parsing of .env files, and an inject.Injector to set values from them.
+//
+// Supported syntax includes:
+//   - KEY=value pairs, one per line, optionally prefixed with 'export'
+//   - Comments, on their own lines or following unquoted values (preceded by whitespace)
+//   - Single quoted values, which are taken literally
+//   - Double quoted values, which support the escapes \n, \r, \t, \", \\ and \$
+//   - Multiline values within single or double quotes
+//   - Expansion of ${VAR}, ${VAR:-default} and $VAR in unquoted and double quoted values
+//
+// Variables are expanded from those previously defined, falling back to the process environment.
+// Values are parsed by literal.Injector.
+package dotenv
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/go-modules/modules/inject"
+	"github.com/go-modules/modules/inject/literal"
+)
+
+// An Env holds variables parsed from .env files.
+type Env map[string]string
+
+// Lookup returns the value of the variable named name, and whether it is defined.
+// May be used as an env.Lookup option.
+func (e Env) Lookup(name string) (string, bool) {
+	value, ok := e[name]
+	return value, ok
+}
+
+// Parse parses a .env file from r.
+func Parse(r io.Reader) (Env, error) {
+	env := make(Env)
+	if err := parseInto(env, r); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// Load parses the .env files at paths. Variables from files later in paths override earlier ones, and may reference
+// them in expansions.
+func Load(paths ...string) (Env, error) {
+	env := make(Env)
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		err = parseInto(env, file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+	}
+	return env, nil
+}
+
+// New returns an inject.Injector which looks up variables by name in env, and sets values via literal.Injector.
+// Only sets value if the variable is defined, otherwise passes by returning (false, nil).
+func New(env Env) inject.Injector {
+	return inject.InjectorFunc(func(value reflect.Value, name string) (bool, error) {
+		envValue, ok := env[name]
+		if !ok {
+			return false, nil
+		}
+		return literal.Injector.Inject(value, envValue)
+	})
+}
+
+// FirstOf returns a lookup function which calls each of lookups in order until one defines the variable.
+// For example, FirstOf(env.Lookup, os.LookupEnv) gives .env values precedence over the process environment.
+func FirstOf(lookups ...func(string) (string, bool)) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		for _, lookup := range lookups {
+			if value, ok := lookup(name); ok {
+				return value, true
+			}
+		}
+		return "", false
+	}
+}
+
+// A SyntaxError indicates a malformed .env file.
+type SyntaxError struct {
+	// The line where the error occurred, starting from 1.
+	Line int
+	msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.msg)
+}
+
+// parseInto parses a .env file from r, adding variables to env.
+func parseInto(env Env, r io.Reader) error {
+	bytes, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	p := &parser{src: string(bytes), line: 1, env: env}
+	return p.parse()
+}
+
+// A parser holds the state of a .env file being parsed.
+type parser struct {
+	src  string
+	pos  int
+	line int
+	env  Env
+}
+
+func (p *parser) errorf(format string, a ...interface{}) error {
+	return &SyntaxError{Line: p.line, msg: fmt.Sprintf(format, a...)}
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() byte {
+	return p.src[p.pos]
+}
+
+// next advances past the current byte, counting lines.
+func (p *parser) next() byte {
+	c := p.src[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+// skipSpace skips spaces and tabs, but not newlines.
+func (p *parser) skipSpace() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.next()
+	}
+}
+
+// skipLine skips to the start of the next line.
+func (p *parser) skipLine() {
+	for !p.eof() && p.next() != '\n' {
+	}
+}
+
+// endLine expects only whitespace or a comment before the end of the line.
+func (p *parser) endLine() error {
+	p.skipSpace()
+	if p.eof() {
+		return nil
+	}
+	switch p.peek() {
+	case '\r', '\n', '#':
+		p.skipLine()
+		return nil
+	}
+	return p.errorf("unexpected character %q after value", p.peek())
+}
+
+func (p *parser) parse() error {
+	for {
+		// Skip blank lines and comments.
+		for !p.eof() && strings.IndexByte(" \t\r\n", p.peek()) >= 0 {
+			p.next()
+		}
+		if p.eof() {
+			return nil
+		}
+		if p.peek() == '#' {
+			p.skipLine()
+			continue
+		}
+
+		key := p.key()
+		if key == "export" && !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+			p.skipSpace()
+			key = p.key()
+		}
+		if key == "" {
+			return p.errorf("expected variable name, found %q", p.peek())
+		}
+		p.skipSpace()
+		if p.eof() || p.peek() != '=' {
+			return p.errorf("expected '=' after variable name %s", key)
+		}
+		p.next()
+		p.skipSpace()
+
+		value, err := p.value()
+		if err != nil {
+			return err
+		}
+		p.env[key] = value
+	}
+}
+
+// key scans a variable name.
+func (p *parser) key() string {
+	start := p.pos
+	for !p.eof() && isKeyChar(p.peek()) {
+		p.next()
+	}
+	return p.src[start:p.pos]
+}
+
+func isKeyChar(c byte) bool {
+	return c == '_' || c == '.' || c == '-' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// value scans a quoted or unquoted value, and the remainder of its line.
+func (p *parser) value() (string, error) {
+	if p.eof() {
+		return "", nil
+	}
+	switch p.peek() {
+	case '\'':
+		start := p.line
+		p.next()
+		end := strings.IndexByte(p.src[p.pos:], '\'')
+		if end < 0 {
+			p.line = start
+			return "", p.errorf("unterminated single quoted value")
+		}
+		value := p.src[p.pos : p.pos+end]
+		for i := 0; i <= end; i++ {
+			p.next()
+		}
+		return value, p.endLine()
+	case '"':
+		start := p.line
+		p.next()
+		var raw strings.Builder
+		for {
+			if p.eof() {
+				p.line = start
+				return "", p.errorf("unterminated double quoted value")
+			}
+			c := p.next()
+			if c == '"' {
+				break
+			}
+			raw.WriteByte(c)
+			if c == '\\' && !p.eof() {
+				raw.WriteByte(p.next())
+			}
+		}
+		value, err := p.expand(raw.String(), true)
+		if err != nil {
+			p.line = start
+			return "", p.errorf("%s", err)
+		}
+		return value, p.endLine()
+	default:
+		start := p.pos
+		for !p.eof() && p.peek() != '\n' {
+			// An unquoted value ends at a comment preceded by whitespace.
+			if p.peek() == '#' && p.pos > start && (p.src[p.pos-1] == ' ' || p.src[p.pos-1] == '\t') {
+				break
+			}
+			p.next()
+		}
+		value := strings.TrimSpace(p.src[start:p.pos])
+		p.skipLine()
+		return p.expand(value, false)
+	}
+}
+
+// expand replaces ${VAR}, ${VAR:-default} and $VAR references in s. If unescape, the escape sequences supported in
+// double quoted values are replaced in the same pass, so that expanded values are not unescaped, or else escaped
+// characters are left as is.
+func (p *parser) expand(s string, unescape bool) (string, error) {
+	if strings.IndexByte(s, '$') < 0 && (!unescape || strings.IndexByte(s, '\\') < 0) {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && unescape:
+			i++
+			if i >= len(s) {
+				return "", fmt.Errorf("trailing backslash")
+			}
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\', '$':
+				b.WriteByte(s[i])
+			default:
+				return "", fmt.Errorf("unsupported escape sequence \\%c", s[i])
+			}
+		case c == '\\' && i+1 < len(s):
+			b.WriteByte(c)
+			b.WriteByte(s[i+1])
+			i++
+		case c == '$' && i+1 < len(s) && s[i+1] == '{':
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				b.WriteString(s[i:])
+				return b.String(), nil
+			}
+			ref := s[i+2 : i+end]
+			name, def, hasDefault := ref, "", false
+			if j := strings.Index(ref, ":-"); j >= 0 {
+				name, def, hasDefault = ref[:j], ref[j+2:], true
+			}
+			if value, ok := p.lookup(name); ok && (value != "" || !hasDefault) {
+				b.WriteString(value)
+			} else {
+				b.WriteString(def)
+			}
+			i += end
+		case c == '$' && i+1 < len(s) && isNameStart(s[i+1]):
+			j := i + 1
+			for j < len(s) && (isNameStart(s[j]) || '0' <= s[j] && s[j] <= '9') {
+				j++
+			}
+			value, _ := p.lookup(s[i+1 : j])
+			b.WriteString(value)
+			i = j - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// lookup looks up name from previously parsed variables, then the process environment.
+func (p *parser) lookup(name string) (string, bool) {
+	if value, ok := p.env[name]; ok {
+		return value, true
+	}
+	return os.LookupEnv(name)
+}
@@ -0,0 +1,119 @@
+package dotenv
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testFile = `# A comment
+PLAIN=value
+export EXPORTED=exported
+SPACED = spaced value   # trailing comment
+HASH=a#b
+SINGLE='literal ${PLAIN} \n'
+DOUBLE="escaped\t\"${PLAIN}\" \$PLAIN"
+MULTI="line 1
+line 2"
+EXPANDED=${PLAIN}-$EXPORTED
+DEFAULTED=${UNDEFINED_DOTENV_TEST_VAR:-fallback}
+EMPTY=
+PORT=8080
+`
+
+func TestParse(t *testing.T) {
+	env, err := Parse(strings.NewReader(testFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Env{
+		"PLAIN":     "value",
+		"EXPORTED":  "exported",
+		"SPACED":    "spaced value",
+		"HASH":      "a#b",
+		"SINGLE":    `literal ${PLAIN} \n`,
+		"DOUBLE":    "escaped\t\"value\" $PLAIN",
+		"MULTI":     "line 1\nline 2",
+		"EXPANDED":  "value-exported",
+		"DEFAULTED": "fallback",
+		"EMPTY":     "",
+		"PORT":      "8080",
+	}
+	if !reflect.DeepEqual(expected, env) {
+		t.Errorf("expected %q but got %q", expected, env)
+	}
+}
+
+func TestParseExpandedBackslashes(t *testing.T) {
+	// Expanded values are not unescaped.
+	env, err := Parse(strings.NewReader(`DIR=C:\new\dir
+QUOTED="${DIR}\t$DIR"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `C:\new\dir` + "\t" + `C:\new\dir`; env["QUOTED"] != expected {
+		t.Errorf("expected %q but got %q", expected, env["QUOTED"])
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, testCase := range []struct {
+		src  string
+		line int
+	}{
+		{"KEY", 1},
+		{"A=1\n=value", 2},
+		{"A=1\nB=\"unterminated\n", 2},
+		{"A='unterminated", 1},
+		{`A="bad \q escape"`, 1},
+		{`A="value" trailing`, 1},
+	} {
+		_, err := Parse(strings.NewReader(testCase.src))
+		if syntaxErr, ok := err.(*SyntaxError); !ok {
+			t.Errorf("%q: expected SyntaxError but got %v", testCase.src, err)
+		} else if syntaxErr.Line != testCase.line {
+			t.Errorf("%q: expected line %d but got %d", testCase.src, testCase.line, syntaxErr.Line)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	base, local := filepath.Join(dir, ".env"), filepath.Join(dir, ".env.local")
+	if err := ioutil.WriteFile(base, []byte("HOST=localhost\nPORT=80\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(local, []byte("PORT=8080\nURL=http://${HOST}:${PORT}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := Load(base, local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Env{"HOST": "localhost", "PORT": "8080", "URL": "http://localhost:8080"}
+	if !reflect.DeepEqual(expected, env) {
+		t.Errorf("expected %q but got %q", expected, env)
+	}
+
+	var port int
+	if ok, err := New(env).Inject(reflect.ValueOf(&port).Elem(), "PORT"); err != nil {
+		t.Error(err)
+	} else if !ok {
+		t.Error("expected value to be set")
+	} else if port != 8080 {
+		t.Errorf("expected 8080 but got %d", port)
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error loading missing file")
+	}
+}
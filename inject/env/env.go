@@ -1,24 +1,138 @@
 // Package env provides a inject.Injector to set values from environment variables.
 // Environment variable strings are parsed by literal.Injector.
+//
+// Configured injectors may be created with New, to prefix variable names, look up variables from a source other
+// than the process environment, or derive variable names from field names when the tag value is empty.
 package env
 
 import (
 	"os"
 	"reflect"
+	"strings"
+	"unicode"
 
 	"github.com/go-modules/modules/inject"
 	"github.com/go-modules/modules/inject/literal"
 )
 
-// Injector is an inject.Injector for parsing environment variables.
-var Injector = inject.InjectorFunc(Inject)
+// Injector is an inject.FieldInjector for parsing environment variables.
+var Injector = New()
 
 // Inject looks up the environment variable by name, and sets the value via literal.Injector.
 // Only sets value if the environment variable is set, otherwise passes by returning (false, nil).
 func Inject(value reflect.Value, name string) (bool, error) {
-	envValue, ok := os.LookupEnv(name)
+	return Injector.Inject(value, name)
+}
+
+// New returns a new inject.FieldInjector for environment variables, configured with options.
+// Without options, it behaves like Injector.
+func New(options ...Option) inject.FieldInjector {
+	i := &injector{lookup: os.LookupEnv}
+	for _, option := range options {
+		option.configure(i)
+	}
+	return i
+}
+
+// WithLookup returns a copy of i with its lookup replaced by the result of wrap, which is passed the current lookup,
+// e.g. to look up variables from additional sources while keeping i's other Options.
+// Returns false if i is not Injector or an injector created by New.
+func WithLookup(i inject.Injector, wrap func(Lookup) Lookup) (inject.Injector, bool) {
+	configured, ok := i.(*injector)
+	if !ok {
+		return i, false
+	}
+	wrapped := *configured
+	wrapped.lookup = wrap(configured.lookup)
+	return &wrapped, true
+}
+
+// An injector looks up environment variables and implements inject.FieldInjector and inject.Describer.
+type injector struct {
+	// Prepended to all variable names.
+	prefix string
+	// Looks up variables by name.
+	lookup func(string) (string, bool)
+	// Derives variable names from field names, when tag values are empty. May be nil.
+	autoName func(string) string
+}
+
+// Inject looks up the environment variable by prefixed name, and sets the value via literal.Injector.
+// Only sets value if the environment variable is set, otherwise passes by returning (false, nil).
+func (i *injector) Inject(value reflect.Value, name string) (bool, error) {
+	if name == "" {
+		return false, nil
+	}
+	envValue, ok := i.lookup(i.prefix + name)
 	if !ok {
 		return false, nil
 	}
 	return literal.Injector.Inject(value, envValue)
 }
+
+// InjectField is like Inject, but derives the variable name from the field name when name is empty and the injector
+// was configured with AutoName.
+func (i *injector) InjectField(ctx inject.InjectionContext, value reflect.Value, name string) (bool, error) {
+	return i.Inject(value, i.name(ctx, name))
+}
+
+// Describe implements inject.Describer by returning the prefixed variable name looked up for the field context and
+// tag value, or an empty string if there is none.
+func (i *injector) Describe(ctx inject.InjectionContext, name string) string {
+	if name = i.name(ctx, name); name == "" {
+		return ""
+	}
+	return i.prefix + name
+}
+
+// name returns the unprefixed variable name for the field context and tag value.
+func (i *injector) name(ctx inject.InjectionContext, name string) string {
+	if name == "" && i.autoName != nil && ctx.Field.Name != "" {
+		return i.autoName(ctx.Field.Name)
+	}
+	return name
+}
+
+// An Option configures an injector created by New.
+type Option interface {
+	configure(*injector)
+}
+
+// Prefix is an Option which is prepended to all variable names, e.g. "MYAPP_".
+type Prefix string
+
+func (p Prefix) configure(i *injector) {
+	i.prefix = string(p)
+}
+
+// Lookup is an Option which replaces os.LookupEnv as the source of variables.
+type Lookup func(string) (string, bool)
+
+func (l Lookup) configure(i *injector) {
+	i.lookup = l
+}
+
+// AutoName is an Option which derives variable names from field names, for fields with empty tag values (e.g. env:"").
+type AutoName func(string) string
+
+func (a AutoName) configure(i *injector) {
+	i.autoName = a
+}
+
+// ScreamingSnake converts a camel case name into upper case words separated by underscores.
+// Runs of upper case letters are treated as acronyms, e.g. "DBHost" becomes "DB_HOST".
+func ScreamingSnake(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && r != '_' && runes[i-1] != '_' {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower)) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -4,6 +4,8 @@ import (
 	"os"
 	"reflect"
 	"testing"
+
+	"github.com/go-modules/modules/inject"
 )
 
 func TestInjector(t *testing.T) {
@@ -45,3 +47,93 @@ func TestInjector(t *testing.T) {
 		}
 	}
 }
+
+func TestNew(t *testing.T) {
+	vars := map[string]string{
+		"MYAPP_DB_HOST": "host",
+		"MYAPP_port":    "8080",
+	}
+	injector := New(Prefix("MYAPP_"), Lookup(func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}), AutoName(ScreamingSnake))
+
+	var port int
+	if ok, err := injector.Inject(reflect.ValueOf(&port).Elem(), "port"); err != nil {
+		t.Error(err)
+	} else if !ok {
+		t.Error("expected value to be set")
+	} else if port != 8080 {
+		t.Errorf("expected 8080 but got %d", port)
+	}
+
+	var host string
+	ctx := inject.InjectionContext{Field: reflect.StructField{Name: "DBHost"}}
+	if ok, err := injector.InjectField(ctx, reflect.ValueOf(&host).Elem(), ""); err != nil {
+		t.Error(err)
+	} else if !ok {
+		t.Error("expected value to be set")
+	} else if host != "host" {
+		t.Errorf("expected %q but got %q", "host", host)
+	}
+
+	if ok, err := injector.Inject(reflect.ValueOf(&host).Elem(), "missing"); err != nil {
+		t.Error(err)
+	} else if ok {
+		t.Error("expected value not to be set")
+	}
+
+	// Describe names the prefixed variable looked up.
+	if name := inject.Describe(injector, ctx, ""); name != "MYAPP_DB_HOST" {
+		t.Errorf("expected %q but got %q", "MYAPP_DB_HOST", name)
+	}
+	if name := inject.Describe(injector, ctx, "port"); name != "MYAPP_port" {
+		t.Errorf("expected %q but got %q", "MYAPP_port", name)
+	}
+	if name := inject.Describe(Injector, inject.InjectionContext{}, ""); name != "" {
+		t.Errorf("expected no name but got %q", name)
+	}
+}
+
+func TestScreamingSnake(t *testing.T) {
+	for _, testCase := range []struct {
+		name     string
+		expected string
+	}{
+		{"Host", "HOST"},
+		{"DBHost", "DB_HOST"},
+		{"dbHost", "DB_HOST"},
+		{"HTTPServerPort", "HTTP_SERVER_PORT"},
+		{"Port8080", "PORT8080"},
+		{"Already_Snake", "ALREADY_SNAKE"},
+	} {
+		if got := ScreamingSnake(testCase.name); got != testCase.expected {
+			t.Errorf("%s: expected %q but got %q", testCase.name, testCase.expected, got)
+		}
+	}
+}
+
+func TestWithLookup(t *testing.T) {
+	vars := map[string]string{"MYAPP_HOST": "host"}
+	injector, ok := WithLookup(New(Prefix("MYAPP_")), func(lookup Lookup) Lookup {
+		return func(name string) (string, bool) {
+			if v, ok := vars[name]; ok {
+				return v, true
+			}
+			return lookup(name)
+		}
+	})
+	if !ok {
+		t.Fatal("expected injector created by New to be wrapped")
+	}
+	var host string
+	if ok, err := injector.Inject(reflect.ValueOf(&host).Elem(), "HOST"); err != nil {
+		t.Fatal(err)
+	} else if !ok || host != "host" {
+		t.Errorf("expected %q but got %q", "host", host)
+	}
+
+	if _, ok := WithLookup(inject.InjectorFunc(Inject), func(lookup Lookup) Lookup { return lookup }); ok {
+		t.Error("expected other injectors not to be wrapped")
+	}
+}
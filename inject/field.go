@@ -0,0 +1,97 @@
+package inject
+
+import (
+	"io"
+	"log"
+	"reflect"
+	"sync"
+
+	"github.com/go-modules/modules/tags"
+)
+
+// An InjectionContext describes the module field being injected.
+type InjectionContext struct {
+	// The field being injected.
+	Field reflect.StructField
+	// The type of the module declaring Field.
+	ModuleType reflect.Type
+	// The name the field is provided as.
+	Name string
+	// The options following the name in the field's 'provide' tag value.
+	Options tags.TagOptions
+	// The Binder's logger, or nil if none is configured.
+	Logger *log.Logger
+	// Registers resources opened during injection to be closed with the Binder, or nil if none is configured.
+	OnClose func(io.Closer)
+	// Shared by Injectors for the duration of a binding, e.g. to reuse parsed files, or nil if none is configured.
+	// Keys should be of unexported types to avoid collisions between packages.
+	Cache *sync.Map
+}
+
+// Tag returns the struct tag of the field being injected, for access to other tag keys.
+func (c InjectionContext) Tag() tags.StructTag {
+	return tags.StructTag(c.Field.Tag)
+}
+
+// Logf logs to c's Logger, if present.
+func (c InjectionContext) Logf(format string, a ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Printf(format, a...)
+	}
+}
+
+// Track registers closer to be closed with the Binder, and returns true, or returns false if c has no OnClose func, in
+// which case the caller is responsible for closing it.
+func (c InjectionContext) Track(closer io.Closer) bool {
+	if c.OnClose == nil {
+		return false
+	}
+	c.OnClose(closer)
+	return true
+}
+
+// A FieldInjector is an Injector which is aware of the context of the field being injected.
+// Binders call InjectField in place of Inject for Injectors implementing this interface.
+type FieldInjector interface {
+	Injector
+	// May set a value based on the field context and tag value.
+	// Returns the same as Injector.Inject.
+	InjectField(InjectionContext, reflect.Value, string) (bool, error)
+}
+
+// FieldInjectorFunc implements FieldInjector.
+type FieldInjectorFunc func(InjectionContext, reflect.Value, string) (bool, error)
+
+// Inject implements the Injector interface by calling f with an empty InjectionContext.
+func (f FieldInjectorFunc) Inject(value reflect.Value, tagValue string) (bool, error) {
+	return f(InjectionContext{}, value, tagValue)
+}
+
+// InjectField implements the FieldInjector interface.
+func (f FieldInjectorFunc) InjectField(ctx InjectionContext, value reflect.Value, tagValue string) (bool, error) {
+	return f(ctx, value, tagValue)
+}
+
+// InjectWithContext calls injector's InjectField method if it implements FieldInjector, or its Inject method otherwise.
+func InjectWithContext(injector Injector, ctx InjectionContext, value reflect.Value, tagValue string) (bool, error) {
+	if fieldInjector, ok := injector.(FieldInjector); ok {
+		return fieldInjector.InjectField(ctx, value, tagValue)
+	}
+	return injector.Inject(value, tagValue)
+}
+
+// A Describer is an Injector which describes the source it looks up for a tag value, e.g. a prefixed environment
+// variable name, for error messages and documentation.
+type Describer interface {
+	Injector
+	// Returns the name of the source looked up for the field context and tag value.
+	Describe(InjectionContext, string) string
+}
+
+// Describe calls injector's Describe method if it implements Describer, or returns tagValue otherwise.
+func Describe(injector Injector, ctx InjectionContext, tagValue string) string {
+	if describer, ok := injector.(Describer); ok {
+		return describer.Describe(ctx, tagValue)
+	}
+	return tagValue
+}
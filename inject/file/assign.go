@@ -0,0 +1,152 @@
+package file
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-modules/modules/inject/literal"
+)
+
+// assign sets the element of target identified by path to value, for decoders of keyed formats (ini, properties,
+// toml). Maps are keyed by path segments, and struct fields are matched by tagKey tags, or else by case-insensitive
+// name. Dotted paths also match fields whose names contain dots. Unmatched struct fields are ignored.
+//
+// Values are strings, which are parsed by literal.Injector, or for toml may also be []interface{} for slices, or
+// typed values for interface targets.
+func assign(target reflect.Value, path []string, value interface{}, tagKey string) error {
+	switch target.Kind() {
+	case reflect.Ptr:
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return assign(target.Elem(), path, value, tagKey)
+	case reflect.Map:
+		if len(path) == 0 {
+			return fmt.Errorf("cannot set value of type %s", target.Type())
+		}
+		if target.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("unsupported map key type %s", target.Type().Key())
+		}
+		if target.IsNil() {
+			target.Set(reflect.MakeMap(target.Type()))
+		}
+		elemType := target.Type().Elem()
+		key := strings.Join(path, ".")
+		rest := []string(nil)
+		if len(path) > 1 && (isContainer(elemType) || elemType.Kind() == reflect.Interface) {
+			key, rest = path[0], path[1:]
+		}
+		elem := reflect.New(elemType).Elem()
+		if existing := target.MapIndex(reflect.ValueOf(key).Convert(target.Type().Key())); existing.IsValid() {
+			elem.Set(existing)
+		}
+		// Nested keys of interface maps are held in maps of the same type.
+		if len(rest) > 0 && elemType.Kind() == reflect.Interface {
+			if elem.IsNil() || elem.Elem().Type() != target.Type() {
+				elem.Set(reflect.MakeMap(target.Type()))
+			}
+			elem = elem.Elem()
+		}
+		if err := assign(elem, rest, value, tagKey); err != nil {
+			return err
+		}
+		target.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), elem)
+		return nil
+	case reflect.Struct:
+		if len(path) == 0 {
+			break
+		}
+		// Prefer the longest matching dotted name.
+		for n := len(path); n > 0; n-- {
+			if field, ok := findField(target, strings.Join(path[:n], "."), tagKey); ok {
+				return assign(field, path[n:], value, tagKey)
+			}
+		}
+		return nil
+	}
+	if len(path) > 0 {
+		return fmt.Errorf("cannot set %s in value of type %s", strings.Join(path, "."), target.Type())
+	}
+	return assignValue(target, value)
+}
+
+// assignValue sets target to value.
+func assignValue(target reflect.Value, value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		if target.Kind() == reflect.Interface {
+			target.Set(reflect.ValueOf(v))
+			return nil
+		}
+		if ok, err := literal.Injector.Inject(target, v); err != nil {
+			return err
+		} else if !ok {
+			return fmt.Errorf("cannot set value of type %s", target.Type())
+		}
+		return nil
+	case []interface{}:
+		switch target.Kind() {
+		case reflect.Interface:
+			target.Set(reflect.ValueOf(v))
+			return nil
+		case reflect.Slice:
+			slice := reflect.MakeSlice(target.Type(), len(v), len(v))
+			for i, elem := range v {
+				if err := assignValue(slice.Index(i), elem); err != nil {
+					return fmt.Errorf("element %d: %s", i, err)
+				}
+			}
+			target.Set(slice)
+			return nil
+		}
+		return fmt.Errorf("cannot set array in value of type %s", target.Type())
+	default:
+		if target.Kind() == reflect.Interface {
+			target.Set(reflect.ValueOf(v))
+			return nil
+		}
+		return assignValue(target, fmt.Sprint(v))
+	}
+}
+
+// isContainer returns true if values of typ may contain keyed values.
+func isContainer(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Struct || typ.Kind() == reflect.Map
+}
+
+// findField returns the exported field of target tagged with tagKey:"name", or else whose name matches name ignoring
+// case, underscores and dashes.
+func findField(target reflect.Value, name, tagKey string) (reflect.Value, bool) {
+	typ := target.Type()
+	match := -1
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if tagName, ok := field.Tag.Lookup(tagKey); ok {
+			tagName = strings.Split(tagName, ",")[0]
+			if tagName == name {
+				return target.Field(i), true
+			} else if tagName != "" {
+				continue
+			}
+		}
+		if match < 0 && normalize(field.Name) == normalize(name) {
+			match = i
+		}
+	}
+	if match < 0 {
+		return reflect.Value{}, false
+	}
+	return target.Field(match), true
+}
+
+// normalize lower cases name and removes underscores and dashes.
+func normalize(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+}
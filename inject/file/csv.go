@@ -0,0 +1,43 @@
+package file
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// decodeCSV decodes CSV files into slices of string slices, or slices of structs (or struct pointers).
+// For structs, the first record is a header of column names, which are matched to fields by 'csv' tags, or else by
+// case-insensitive name. Unmatched columns are ignored, and values are parsed via literal.Injector.
+func decodeCSV(r io.Reader, v interface{}) error {
+	target := reflect.ValueOf(v).Elem()
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return err
+	}
+	if target.Type() == reflect.TypeOf([][]string{}) {
+		target.Set(reflect.ValueOf(records))
+		return nil
+	}
+	if target.Kind() != reflect.Slice || !isContainer(target.Type().Elem()) || target.Type().Elem().Kind() == reflect.Map {
+		return fmt.Errorf("csv may only be decoded into slices of string slices or structs, not %s", target.Type())
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	header := records[0]
+	slice := reflect.MakeSlice(target.Type(), 0, len(records)-1)
+	for i, record := range records[1:] {
+		elem := reflect.New(target.Type().Elem()).Elem()
+		for j, value := range record {
+			if err := assign(elem, []string{header[j]}, value, "csv"); err != nil {
+				return fmt.Errorf("record %d, column %s: %s", i+1, header[j], err)
+			}
+		}
+		slice = reflect.Append(slice, elem)
+	}
+	target.Set(slice)
+	return nil
+}
@@ -0,0 +1,93 @@
+package file
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-modules/modules/inject/literal"
+)
+
+// A Decoder decodes the contents of r into v, which is a non-nil pointer.
+type Decoder func(r io.Reader, v interface{}) error
+
+// decoders holds registered Decoders by file type.
+var decoders = struct {
+	sync.RWMutex
+	m map[string]Decoder
+}{m: map[string]Decoder{
+	"txt": decodeText,
+	"json": func(r io.Reader, v interface{}) error {
+		return json.NewDecoder(r).Decode(v)
+	},
+	"xml": func(r io.Reader, v interface{}) error {
+		return xml.NewDecoder(r).Decode(v)
+	},
+	"gob": func(r io.Reader, v interface{}) error {
+		return gob.NewDecoder(r).Decode(v)
+	},
+	"ini":        decodeINI,
+	"properties": decodeProperties,
+	"csv":        decodeCSV,
+	"toml":       decodeTOML,
+}}
+
+// RegisterDecoder registers decoder for files of fileType (i.e. with that extension, or type option), replacing any
+// previously registered Decoder.
+func RegisterDecoder(fileType string, decoder Decoder) {
+	decoders.Lock()
+	decoders.m[fileType] = decoder
+	decoders.Unlock()
+}
+
+// FileTypes returns the sorted file types with registered Decoders.
+func FileTypes() []string {
+	decoders.RLock()
+	types := make([]string, 0, len(decoders.m))
+	for fileType := range decoders.m {
+		types = append(types, fileType)
+	}
+	decoders.RUnlock()
+	sort.Strings(types)
+	return types
+}
+
+// Decode decodes the contents of r into value, with the Decoder registered for fileType.
+func Decode(r io.Reader, fileType string, value reflect.Value) error {
+	decoders.RLock()
+	decoder, ok := decoders.m[fileType]
+	decoders.RUnlock()
+	if !ok {
+		return fmt.Errorf("unrecognized file type %s; registered types: %s", fileType, strings.Join(FileTypes(), ", "))
+	}
+	return decoder(r, decodeTarget(value))
+}
+
+// decodeTarget returns a pointer for decoding into value.
+// Addressable values are decoded via their address, and nil pointers are allocated.
+func decodeTarget(value reflect.Value) interface{} {
+	if value.CanAddr() {
+		return value.Addr().Interface()
+	}
+	if value.Kind() == reflect.Ptr && value.IsNil() && value.CanSet() {
+		value.Set(reflect.New(value.Type().Elem()))
+	}
+	return value.Interface()
+}
+
+// decodeText parses the contents of r via literal.Injector.
+func decodeText(r io.Reader, v interface{}) error {
+	bytes, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	_, err = literal.Injector.Inject(reflect.ValueOf(v).Elem(), string(bytes))
+	return err
+}
@@ -0,0 +1,203 @@
+package file
+
+import (
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type database struct {
+	Host    string
+	Port    int
+	Options map[string]string
+}
+
+type appConfig struct {
+	Name     string `ini:"app_name" properties:"app.name" toml:"app_name"`
+	Debug    bool
+	Database database `ini:"db" properties:"db" toml:"db"`
+	Tags     []string
+}
+
+func TestDecodeINI(t *testing.T) {
+	src := `; comment
+app_name = test
+debug: true
+
+[db]
+host = "localhost"
+port = 5432
+
+[db.options]
+sslmode = disable
+`
+	var config appConfig
+	if err := Decode(strings.NewReader(src), "ini", reflect.ValueOf(&config).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	expected := appConfig{Name: "test", Debug: true, Database: database{"localhost", 5432, map[string]string{"sslmode": "disable"}}}
+	if !reflect.DeepEqual(expected, config) {
+		t.Errorf("expected %+v but got %+v", expected, config)
+	}
+
+	var sections map[string]map[string]string
+	if err := Decode(strings.NewReader(src), "ini", reflect.ValueOf(&sections).Elem()); err == nil {
+		t.Error("expected error for top level keys in map of sections")
+	}
+	var flat map[string]string
+	if err := Decode(strings.NewReader(src), "ini", reflect.ValueOf(&flat).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if flat["db.options.sslmode"] != "disable" || flat["app_name"] != "test" {
+		t.Errorf("unexpected flat map: %v", flat)
+	}
+
+	if err := Decode(strings.NewReader("[db\n"), "ini", reflect.ValueOf(&flat).Elem()); err == nil {
+		t.Error("expected error for malformed section")
+	}
+}
+
+func TestDecodeProperties(t *testing.T) {
+	src := `# comment
+! another comment
+app.name = test
+debug:true
+db.host localhost
+db.port=54\
+  32
+db.options.key\=with\:separators = tab\there é
+`
+	var config appConfig
+	if err := Decode(strings.NewReader(src), "properties", reflect.ValueOf(&config).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	expected := appConfig{Name: "test", Debug: true, Database: database{"localhost", 5432, map[string]string{"key=with:separators": "tab\there é"}}}
+	if !reflect.DeepEqual(expected, config) {
+		t.Errorf("expected %+v but got %+v", expected, config)
+	}
+
+	var flat map[string]string
+	if err := Decode(strings.NewReader(src), "properties", reflect.ValueOf(&flat).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if flat["db.host"] != "localhost" || len(flat) != 5 {
+		t.Errorf("unexpected flat map: %v", flat)
+	}
+}
+
+type record struct {
+	Name  string `csv:"full name"`
+	Age   int
+	Admin bool
+}
+
+func TestDecodeCSV(t *testing.T) {
+	src := "full name,age,admin,ignored\nAlice,30,true,x\nBob,25,false,y\n"
+	var records []record
+	if err := Decode(strings.NewReader(src), "csv", reflect.ValueOf(&records).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	expected := []record{{"Alice", 30, true}, {"Bob", 25, false}}
+	if !reflect.DeepEqual(expected, records) {
+		t.Errorf("expected %+v but got %+v", expected, records)
+	}
+
+	var pointers []*record
+	if err := Decode(strings.NewReader(src), "csv", reflect.ValueOf(&pointers).Elem()); err != nil {
+		t.Fatal(err)
+	} else if len(pointers) != 2 || *pointers[1] != expected[1] {
+		t.Errorf("expected %+v but got %+v", expected, pointers)
+	}
+
+	var raw [][]string
+	if err := Decode(strings.NewReader(src), "csv", reflect.ValueOf(&raw).Elem()); err != nil {
+		t.Fatal(err)
+	} else if len(raw) != 3 || raw[2][0] != "Bob" {
+		t.Errorf("unexpected records: %v", raw)
+	}
+
+	var invalid []record
+	if err := Decode(strings.NewReader("age\nx\n"), "csv", reflect.ValueOf(&invalid).Elem()); err == nil {
+		t.Error("expected error parsing invalid value")
+	}
+}
+
+func TestDecodeTOML(t *testing.T) {
+	src := `# comment
+app_name = "test \"app\""
+debug = true # trailing comment
+tags = [
+  "a", 'b\c', # comment
+]
+
+[db]
+host = 'localhost'
+port = 5_432
+options.sslmode = "disable"
+`
+	var config appConfig
+	if err := Decode(strings.NewReader(src), "toml", reflect.ValueOf(&config).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	expected := appConfig{
+		Name:     `test "app"`,
+		Debug:    true,
+		Database: database{"localhost", 5432, map[string]string{"sslmode": "disable"}},
+		Tags:     []string{"a", `b\c`},
+	}
+	if !reflect.DeepEqual(expected, config) {
+		t.Errorf("expected %+v but got %+v", expected, config)
+	}
+
+	var generic map[string]interface{}
+	if err := Decode(strings.NewReader("a = 1\nb = 1.5\n[t]\nc = [true, \"x\"]\n"), "toml", reflect.ValueOf(&generic).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	expectedGeneric := map[string]interface{}{"a": int64(1), "b": 1.5, "t": map[string]interface{}{"c": []interface{}{true, "x"}}}
+	if !reflect.DeepEqual(expectedGeneric, generic) {
+		t.Errorf("expected %v but got %v", expectedGeneric, generic)
+	}
+
+	for _, src := range []string{
+		"a = {b = 1}",
+		"[[tables]]",
+		`a = """multi"""`,
+		"a = 1979-05-27",
+		"a = \"unterminated",
+		"a = 1 b = 2",
+		"a",
+	} {
+		if err := Decode(strings.NewReader(src), "toml", reflect.ValueOf(&generic).Elem()); err == nil {
+			t.Errorf("%q: expected error", src)
+		}
+	}
+}
+
+func TestRegisterDecoder(t *testing.T) {
+	var s string
+	err := Decode(strings.NewReader("value"), "upper", reflect.ValueOf(&s).Elem())
+	if err == nil || !strings.Contains(err.Error(), "registered types: csv, gob, ini, json, properties, toml, txt, xml") {
+		t.Errorf("expected error listing registered types, got %v", err)
+	}
+
+	defer func() {
+		decoders.Lock()
+		delete(decoders.m, "upper")
+		decoders.Unlock()
+	}()
+	RegisterDecoder("upper", func(r io.Reader, v interface{}) error {
+		bytes, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		*v.(*string) = strings.ToUpper(string(bytes))
+		return nil
+	})
+	if err := Decode(strings.NewReader("value"), "upper", reflect.ValueOf(&s).Elem()); err != nil {
+		t.Fatal(err)
+	} else if s != "VALUE" {
+		t.Errorf("expected %q but got %q", "VALUE", s)
+	}
+}
@@ -0,0 +1,66 @@
+package file
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/go-modules/modules/inject"
+)
+
+// injectDir sets value, a map with string keys, from the files in the directory described by tag.
+func (i *injector) injectDir(ctx inject.InjectionContext, value reflect.Value, tag fileTag) (bool, error) {
+	if value.Kind() != reflect.Map || value.Type().Key().Kind() != reflect.String {
+		return false, fmt.Errorf("directories may only be injected into maps with string keys, not %s", value.Type())
+	}
+	dirName, pattern := filepath.Clean(tag.name), "*"
+	if base := filepath.Base(dirName); strings.ContainsAny(base, `*?[\`) {
+		dirName, pattern = filepath.Dir(dirName), base
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return false, fmt.Errorf("invalid pattern %q in file tag value: %s", pattern, err)
+	}
+
+	dir, err := i.find(dirName)
+	if err != nil {
+		return false, err
+	}
+	defer dir.Close()
+	readDir, ok := dir.(fs.ReadDirFile)
+	if !ok {
+		return false, fmt.Errorf("%s is not a directory", dirName)
+	}
+	entries, err := readDir.ReadDir(-1)
+	if err != nil {
+		return false, fmt.Errorf("unable to read directory %s: %s", dirName, err)
+	}
+
+	m := reflect.MakeMap(value.Type())
+	elemType := value.Type().Elem()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		if ok, _ := filepath.Match(pattern, name); !ok {
+			continue
+		}
+		entryTag := fileTag{name: filepath.Join(dirName, name), fileType: tag.fileType}
+		entryTag.deriveType()
+		elem := reflect.New(elemType).Elem()
+		if elemType.Kind() == reflect.String {
+			// Strings are set to file contents, regardless of file type.
+			var contents []byte
+			if _, err := i.inject(ctx, reflect.ValueOf(&contents).Elem(), entryTag); err != nil {
+				return false, err
+			}
+			elem.SetString(string(contents))
+		} else if _, err := i.inject(ctx, elem, entryTag); err != nil {
+			return false, err
+		}
+		m.SetMapIndex(reflect.ValueOf(name).Convert(value.Type().Key()), elem)
+	}
+	return true, set(value, m)
+}
@@ -0,0 +1,99 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	confDir := filepath.Join(dir, "conf.d")
+	if err := os.MkdirAll(filepath.Join(confDir, "..data"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(confDir, "sub"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range map[string]string{
+		"a.json":     `{"test": "a"}`,
+		"b.json":     `{"test": "b"}`,
+		"LOG_LEVEL":  "debug",
+		".hidden":    "hidden",
+		"sub/c.json": `{"test": "c"}`,
+	} {
+		if err := ioutil.WriteFile(filepath.Join(confDir, name), []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var values map[string]string
+	if ok, err := Inject(reflect.ValueOf(&values).Elem(), confDir+"/,dir"); err != nil || !ok {
+		t.Fatalf("expected map to be set: %v", err)
+	}
+	expectedValues := map[string]string{"a.json": `{"test": "a"}`, "b.json": `{"test": "b"}`, "LOG_LEVEL": "debug"}
+	if !reflect.DeepEqual(expectedValues, values) {
+		t.Errorf("expected %v but got %v", expectedValues, values)
+	}
+
+	var raw map[string][]byte
+	if _, err := Inject(reflect.ValueOf(&raw).Elem(), confDir+",dir"); err != nil {
+		t.Fatal(err)
+	} else if len(raw) != 3 || string(raw["LOG_LEVEL"]) != "debug" {
+		t.Errorf("unexpected map: %q", raw)
+	}
+
+	var decoded map[string]JsonType
+	if _, err := Inject(reflect.ValueOf(&decoded).Elem(), confDir+"/*.json,dir"); err != nil {
+		t.Fatal(err)
+	}
+	expectedDecoded := map[string]JsonType{"a.json": {"a"}, "b.json": {"b"}}
+	if !reflect.DeepEqual(expectedDecoded, decoded) {
+		t.Errorf("expected %v but got %v", expectedDecoded, decoded)
+	}
+	var pointers map[string]*JsonType
+	if _, err := Inject(reflect.ValueOf(&pointers).Elem(), confDir+"/a*,dir,json"); err != nil {
+		t.Fatal(err)
+	} else if len(pointers) != 1 || pointers["a.json"].Test != "a" {
+		t.Errorf("unexpected map: %v", pointers)
+	}
+
+	// Files without a type may not be decoded.
+	if _, err := Inject(reflect.ValueOf(&decoded).Elem(), confDir+",dir"); err == nil {
+		t.Error("expected error decoding file without type")
+	}
+	var s string
+	if _, err := Inject(reflect.ValueOf(&s).Elem(), confDir+",dir"); err == nil {
+		t.Error("expected error for non-map value")
+	}
+	if _, err := Inject(reflect.ValueOf(&values).Elem(), confDir+"/[,dir"); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+	if _, err := Inject(reflect.ValueOf(&values).Elem(), confDir+"#/a,dir"); err == nil {
+		t.Error("expected error selecting fragment from directory")
+	}
+	if _, err := Inject(reflect.ValueOf(&values).Elem(), filepath.Join(confDir, "LOG_LEVEL")+",dir"); err == nil {
+		t.Error("expected error for file")
+	}
+	if ok, err := Inject(reflect.ValueOf(&values).Elem(), filepath.Join(dir, "missing")+",dir,optional"); err != nil || ok {
+		t.Errorf("expected (false, nil) for missing optional directory, but got (%t, %v)", ok, err)
+	}
+
+	var fromFS map[string]string
+	injector := New(FS{fstest.MapFS{
+		"conf.d/a.txt": {Data: []byte("a")},
+		"conf.d/b.txt": {Data: []byte("b")},
+	}})
+	if _, err := injector.Inject(reflect.ValueOf(&fromFS).Elem(), "conf.d,dir"); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(map[string]string{"a.txt": "a", "b.txt": "b"}, fromFS) {
+		t.Errorf("unexpected map: %v", fromFS)
+	}
+}
@@ -0,0 +1,213 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// A document is a parsed file, from which fragments may be selected and decoded into values.
+type document interface {
+	// decode decodes the fragment at pointer into value.
+	decode(pointer []string, value reflect.Value) error
+}
+
+// A documentKey identifies a parsed document cached for a binding.
+// Documents are keyed by expected digest too, so that each is verified against the digest it is selected with.
+type documentKey struct {
+	injector           *injector
+	fileName, fileType string
+	sha256             string
+}
+
+// parseDocument parses the contents of r as a document of fileType.
+// Supported types are json, xml, and the keyed formats ini, properties and toml.
+func parseDocument(r io.Reader, fileType string) (document, error) {
+	switch fileType {
+	case "json":
+		var tree interface{}
+		decoder := json.NewDecoder(r)
+		decoder.UseNumber()
+		if err := decoder.Decode(&tree); err != nil {
+			return nil, err
+		}
+		return jsonDocument{tree}, nil
+	case "xml":
+		var root xmlNode
+		if err := xml.NewDecoder(r).Decode(&root); err != nil {
+			return nil, err
+		}
+		return xmlDocument{root}, nil
+	case "ini", "properties", "toml":
+		tree := make(map[string]interface{})
+		if err := Decode(r, fileType, reflect.ValueOf(&tree).Elem()); err != nil {
+			return nil, err
+		}
+		return keyedDocument{tree, fileType}, nil
+	}
+	return nil, fmt.Errorf("selecting fragments is not supported for file type %s", fileType)
+}
+
+// parsePointer parses a pointer of the form /a/b/c into its reference tokens, unescaping ~1 to / and ~0 to ~ as in
+// JSON Pointer (RFC 6901). The empty pointer refers to the whole document.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("invalid pointer %q; must be empty or start with '/'", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// formatPointer formats tokens as a pointer, for error messages.
+func formatPointer(tokens []string) string {
+	escaped := make([]string, len(tokens))
+	for i, token := range tokens {
+		escaped[i] = "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+	}
+	return strings.Join(escaped, "")
+}
+
+// A jsonDocument holds a JSON document decoded into interface{} values, with numbers as json.Number.
+type jsonDocument struct {
+	tree interface{}
+}
+
+// decode selects the fragment at pointer, and decodes it into value via encoding/json.
+func (d jsonDocument) decode(pointer []string, value reflect.Value) error {
+	fragment := d.tree
+	for i, token := range pointer {
+		switch node := fragment.(type) {
+		case map[string]interface{}:
+			child, ok := node[token]
+			if !ok {
+				return fmt.Errorf("no value at %s", formatPointer(pointer[:i+1]))
+			}
+			fragment = child
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return fmt.Errorf("no value at %s", formatPointer(pointer[:i+1]))
+			}
+			fragment = node[index]
+		default:
+			return fmt.Errorf("no value at %s", formatPointer(pointer[:i+1]))
+		}
+	}
+	bytes, err := json.Marshal(fragment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, decodeTarget(value))
+}
+
+// An xmlNode holds an element of an XML document, and its raw contents.
+type xmlNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Inner   []byte     `xml:",innerxml"`
+	Nodes   []xmlNode  `xml:",any"`
+}
+
+// An xmlDocument holds the root element of an XML document.
+type xmlDocument struct {
+	root xmlNode
+}
+
+// decode selects the element at pointer, starting with the root element's name, and decodes it into value via
+// encoding/xml. A final token of the form @name selects an attribute, which is parsed via literal.Injector.
+func (d xmlDocument) decode(pointer []string, value reflect.Value) error {
+	if len(pointer) == 0 {
+		return d.root.decode(value)
+	}
+	if pointer[0] != d.root.XMLName.Local {
+		return fmt.Errorf("no element at %s", formatPointer(pointer[:1]))
+	}
+	node := d.root
+	for i, token := range pointer[1:] {
+		if strings.HasPrefix(token, "@") && i == len(pointer)-2 {
+			for _, attr := range node.Attrs {
+				if attr.Name.Local == token[1:] {
+					return assignValue(value, attr.Value)
+				}
+			}
+			return fmt.Errorf("no attribute at %s", formatPointer(pointer))
+		}
+		found := false
+		for _, child := range node.Nodes {
+			if child.XMLName.Local == token {
+				node, found = child, true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no element at %s", formatPointer(pointer[:i+2]))
+		}
+	}
+	return node.decode(value)
+}
+
+// decode decodes n into value via encoding/xml.
+func (n xmlNode) decode(value reflect.Value) error {
+	var buf bytes.Buffer
+	buf.WriteString("<" + n.XMLName.Local)
+	for _, attr := range n.Attrs {
+		buf.WriteString(" " + attr.Name.Local + `="`)
+		xml.EscapeText(&buf, []byte(attr.Value))
+		buf.WriteString(`"`)
+	}
+	buf.WriteString(">")
+	buf.Write(n.Inner)
+	buf.WriteString("</" + n.XMLName.Local + ">")
+	return xml.Unmarshal(buf.Bytes(), decodeTarget(value))
+}
+
+// A keyedDocument holds an ini, properties or toml document decoded into nested maps.
+type keyedDocument struct {
+	tree     map[string]interface{}
+	fileType string
+}
+
+// decode selects the section or value at pointer, and assigns it to value like the file type's Decoder.
+func (d keyedDocument) decode(pointer []string, value reflect.Value) error {
+	var fragment interface{} = d.tree
+	for i, token := range pointer {
+		node, ok := fragment.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("no value at %s", formatPointer(pointer[:i+1]))
+		}
+		if fragment, ok = node[token]; !ok {
+			return fmt.Errorf("no value at %s", formatPointer(pointer[:i+1]))
+		}
+	}
+	if value.Kind() == reflect.Interface {
+		value.Set(reflect.ValueOf(fragment))
+		return nil
+	}
+	return assignTree(value, nil, fragment, d.fileType)
+}
+
+// assignTree assigns each value in tree to target, at its path following prefix.
+func assignTree(target reflect.Value, prefix []string, tree interface{}, tagKey string) error {
+	node, ok := tree.(map[string]interface{})
+	if !ok {
+		return assign(target, prefix, tree, tagKey)
+	}
+	for key, child := range node {
+		path := append(append([]string(nil), prefix...), key)
+		if err := assignTree(target, path, child, tagKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
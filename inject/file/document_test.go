@@ -0,0 +1,120 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/go-modules/modules/inject"
+)
+
+func TestSelect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for name, contents := range map[string]string{
+		"config.json": `{"database": {"host": "localhost", "port": 5432, "options": {"sslmode": "disable"}}, "a/b": ["x", "y"]}`,
+		"config.xml":  `<config><database host="localhost"><port>5432</port><options><sslmode>disable</sslmode></options></database></config>`,
+		"config.ini":  "[database]\nhost = localhost\nport = 5432\n[database.options]\nsslmode = disable\n",
+		"config.toml": "[database]\nhost = \"localhost\"\nport = 5432\noptions.sslmode = \"disable\"\n",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	type xmlDatabase struct {
+		Host    string `xml:"host,attr"`
+		Port    int    `xml:"port"`
+		Options struct {
+			SSLMode string `xml:"sslmode"`
+		} `xml:"options"`
+	}
+
+	for _, testCase := range []struct {
+		tagValue string
+		value    interface{}
+		expected interface{}
+	}{
+		{"config.json#/database/host", new(string), "localhost"},
+		{"config.json#/database/port", new(int), 5432},
+		{"config.json#/database", new(database), database{"localhost", 5432, map[string]string{"sslmode": "disable"}}},
+		{"config.json#/a~1b/1", new(string), "y"},
+		{"config.json#/database", new(map[string]interface{}), map[string]interface{}{"host": "localhost", "port": 5432.0, "options": map[string]interface{}{"sslmode": "disable"}}},
+		{"config.xml#/config/database/@host", new(string), "localhost"},
+		{"config.xml#/config/database/port", new(int), 5432},
+		{"config.xml#/config/database", new(xmlDatabase), xmlDatabase{"localhost", 5432, struct {
+			SSLMode string `xml:"sslmode"`
+		}{"disable"}}},
+		{"config.ini#/database/port", new(int), 5432},
+		{"config.ini#/database", new(database), database{"localhost", 5432, map[string]string{"sslmode": "disable"}}},
+		{"config.ini#/database/options", new(map[string]string), map[string]string{"sslmode": "disable"}},
+		{"config.toml#/database/port", new(int), 5432},
+		{"config.toml#/database/options/sslmode", new(interface{}), "disable"},
+	} {
+		value := reflect.ValueOf(testCase.value).Elem()
+		if ok, err := Inject(value, filepath.Join(dir, testCase.tagValue)); err != nil {
+			t.Errorf("%s: %s", testCase.tagValue, err)
+		} else if !ok {
+			t.Errorf("%s: expected value to be set", testCase.tagValue)
+		} else if !reflect.DeepEqual(testCase.expected, value.Interface()) {
+			t.Errorf("%s: expected %v but got %v", testCase.tagValue, testCase.expected, value)
+		}
+	}
+
+	for _, tagValue := range []string{
+		"config.json#/database/user",
+		"config.json#/a~1b/2",
+		"config.json#database",
+		"config.xml#/other",
+		"config.xml#/config/database/@user",
+		"config.ini#/database/host/x",
+		"config.json#/database,gob",
+	} {
+		var s string
+		if _, err := Inject(reflect.ValueOf(&s).Elem(), filepath.Join(dir, tagValue)); err == nil {
+			t.Errorf("%s: expected error", tagValue)
+		}
+	}
+	var b []byte
+	if _, err := Inject(reflect.ValueOf(&b).Elem(), filepath.Join(dir, "config.json#/database")); err == nil {
+		t.Error("expected error selecting fragment for []byte")
+	}
+}
+
+func TestSelectCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(path, []byte(`{"host": "localhost", "port": 5432}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := inject.InjectionContext{Cache: &sync.Map{}}
+	var host string
+	if _, err := InjectField(ctx, reflect.ValueOf(&host).Elem(), path+"#/host"); err != nil {
+		t.Fatal(err)
+	}
+	// The parsed document is reused, so the file is not read again.
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	var port int
+	if _, err := InjectField(ctx, reflect.ValueOf(&port).Elem(), path+"#/port"); err != nil {
+		t.Fatal(err)
+	}
+	if host != "localhost" || port != 5432 {
+		t.Errorf("expected localhost:5432 but got %s:%d", host, port)
+	}
+	if _, err := Inject(reflect.ValueOf(&port).Elem(), path+"#/port"); err == nil {
+		t.Error("expected error without cache")
+	}
+}
@@ -0,0 +1,183 @@
+package file
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// KeyEnv is an Option naming an environment variable which holds the base64 encoded AES key for decrypting .enc files.
+type KeyEnv string
+
+func (k KeyEnv) configure(i *injector) {
+	i.key = func() ([]byte, error) {
+		encoded, ok := os.LookupEnv(string(k))
+		if !ok {
+			return nil, fmt.Errorf("key environment variable %s is not set", string(k))
+		}
+		return decodeKey(encoded)
+	}
+}
+
+// KeyFile is an Option naming a file which holds the base64 encoded AES key for decrypting .enc files.
+type KeyFile string
+
+func (k KeyFile) configure(i *injector) {
+	i.key = func() ([]byte, error) {
+		encoded, err := ioutil.ReadFile(string(k))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read key file: %s", err)
+		}
+		return decodeKey(string(encoded))
+	}
+}
+
+// decodeKey decodes a base64 encoded AES key, ignoring surrounding whitespace.
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 key: %s", err)
+	}
+	return key, nil
+}
+
+// decompressors decompress files by extension.
+var decompressors = map[string]func(io.Reader) (io.ReadCloser, error){
+	"gz": func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r)
+	},
+	"bz2": func(r io.Reader) (io.ReadCloser, error) {
+		return ioutil.NopCloser(bzip2.NewReader(r)), nil
+	},
+	"zlib": zlib.NewReader,
+}
+
+// isEncoding returns true if files with extension ext are compressed or encrypted.
+func isEncoding(ext string) bool {
+	_, ok := decompressors[ext]
+	return ok || ext == "enc"
+}
+
+// Encrypt seals plaintext in the envelope format of .enc files: a random 12 byte nonce followed by the AES-GCM
+// ciphertext. The key must be 16, 24 or 32 bytes long, to select AES-128, AES-192 or AES-256.
+func Encrypt(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt opens an envelope sealed by Encrypt.
+func Decrypt(key, envelope []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope) < gcm.NonceSize() {
+		return nil, errors.New("encrypted envelope is too short")
+	}
+	return gcm.Open(nil, envelope[:gcm.NonceSize()], envelope[gcm.NonceSize():], nil)
+}
+
+// newGCM returns an AES-GCM AEAD for key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// openDecoded opens the file described by tag, verifies it, and returns a reader of its decrypted and decompressed
+// contents. Closing the reader closes the file.
+func (i *injector) openDecoded(tag fileTag) (io.ReadCloser, error) {
+	expected, err := i.expectedDigest(tag)
+	if err != nil {
+		return nil, err
+	}
+	file, err := i.open(tag.name)
+	if err != nil {
+		return nil, err
+	}
+	decoded := &decodedReader{i.reader(tag.name, file), []io.Closer{file}}
+	// Verify the file before decrypting or decoding it.
+	if expected != "" {
+		contents, err := verify(tag.name, decoded.Reader, expected)
+		if err != nil {
+			decoded.Close()
+			return nil, err
+		}
+		decoded.Reader = bytes.NewReader(contents)
+	}
+	for _, encoding := range tag.encodings {
+		if encoding == "enc" {
+			decoded.Reader, err = i.decrypt(decoded.Reader)
+		} else {
+			var rc io.ReadCloser
+			if rc, err = decompressors[encoding](decoded.Reader); err == nil {
+				decoded.Reader = rc
+				decoded.closers = append(decoded.closers, rc)
+			}
+		}
+		if err != nil {
+			decoded.Close()
+			return nil, fmt.Errorf("unable to read file %s: %s", tag.name, err)
+		}
+		// Limit decoded contents too, e.g. to reject compression bombs.
+		decoded.Reader = i.reader(tag.name, decoded.Reader)
+	}
+	return decoded, nil
+}
+
+// decrypt returns a reader of the decrypted contents of r.
+func (i *injector) decrypt(r io.Reader) (io.Reader, error) {
+	if i.key == nil {
+		return nil, errors.New("no key configured for encrypted file")
+	}
+	key, err := i.key()
+	if err != nil {
+		return nil, err
+	}
+	envelope, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := Decrypt(key, envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %s", err)
+	}
+	return bytes.NewReader(plaintext), nil
+}
+
+// A decodedReader reads decoded file contents, and closes the file and any decompressors.
+type decodedReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+// Close closes the decompressors and file, in reverse order.
+func (d *decodedReader) Close() error {
+	var err error
+	for i := len(d.closers) - 1; i >= 0; i-- {
+		if closeErr := d.closers[i].Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
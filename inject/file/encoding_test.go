@@ -0,0 +1,154 @@
+package file
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEncodings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	key := bytes.Repeat([]byte{7}, 32)
+	encodedKey := base64.StdEncoding.EncodeToString(key)
+	keyFile := filepath.Join(dir, "config.key")
+	if err := ioutil.WriteFile(keyFile, []byte(encodedKey+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FILE_TEST_KEY", encodedKey)
+
+	plaintext := []byte(`{"test": "test"}`)
+	gzipped := compress(t, plaintext, func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) })
+	encrypted, err := Encrypt(key, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encryptedGzip, err := Encrypt(key, gzipped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range map[string][]byte{
+		"config.json.gz":      gzipped,
+		"config.json.zlib":    compress(t, plaintext, func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }),
+		"secrets.json.enc":    encrypted,
+		"secrets.json.gz.enc": encryptedGzip,
+		"secrets.enc":         encrypted,
+		"corrupt.json.gz":     plaintext,
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), contents, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, injector := range []struct {
+		name   string
+		option Option
+	}{
+		{"KeyEnv", KeyEnv("FILE_TEST_KEY")},
+		{"KeyFile", KeyFile(keyFile)},
+	} {
+		i := New(Root(dir), injector.option)
+		for _, tagValue := range []string{
+			"config.json.gz",
+			"config.json.zlib",
+			"secrets.json.enc",
+			"secrets.json.gz.enc",
+			"secrets.enc,json",
+		} {
+			var value JsonType
+			if ok, err := i.Inject(reflect.ValueOf(&value).Elem(), tagValue); err != nil {
+				t.Errorf("%s: %s: %s", injector.name, tagValue, err)
+			} else if !ok || value.Test != "test" {
+				t.Errorf("%s: %s: expected value to be decoded, but got %+v", injector.name, tagValue, value)
+			}
+		}
+
+		var test string
+		if _, err := i.Inject(reflect.ValueOf(&test).Elem(), "secrets.json.gz.enc#/test"); err != nil {
+			t.Errorf("%s: %s", injector.name, err)
+		} else if test != "test" {
+			t.Errorf("%s: expected %q but got %q", injector.name, "test", test)
+		}
+
+		var contents []byte
+		if _, err := i.Inject(reflect.ValueOf(&contents).Elem(), "secrets.json.gz.enc"); err != nil {
+			t.Errorf("%s: %s", injector.name, err)
+		} else if !bytes.Equal(plaintext, contents) {
+			t.Errorf("%s: expected %q but got %q", injector.name, plaintext, contents)
+		}
+
+		var reader io.ReadCloser
+		if _, err := i.Inject(reflect.ValueOf(&reader).Elem(), "config.json.gz"); err != nil {
+			t.Errorf("%s: %s", injector.name, err)
+		} else if contents, err := ioutil.ReadAll(reader); err != nil || !bytes.Equal(plaintext, contents) {
+			t.Errorf("%s: expected %q but got %q (%v)", injector.name, plaintext, contents, err)
+		} else if err := reader.Close(); err != nil {
+			t.Error(err)
+		}
+	}
+
+	var value JsonType
+	var file *os.File
+	for _, testCase := range []struct {
+		name     string
+		injector Option
+		value    interface{}
+		tagValue string
+	}{
+		{"no key", Root(dir), &value, "secrets.json.enc"},
+		{"missing key variable", KeyEnv("FILE_TEST_MISSING_KEY"), &value, "secrets.json.enc"},
+		{"invalid gzip", Root(dir), &value, "corrupt.json.gz"},
+		{"*os.File", Root(dir), &file, "config.json.gz"},
+	} {
+		i := New(Root(dir), testCase.injector)
+		if _, err := i.Inject(reflect.ValueOf(testCase.value).Elem(), testCase.tagValue); err == nil {
+			t.Errorf("%s: expected error", testCase.name)
+		}
+	}
+}
+
+func TestEncrypt(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 16)
+	envelope, err := Encrypt(key, []byte("plaintext"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plaintext, err := Decrypt(key, envelope); err != nil {
+		t.Error(err)
+	} else if string(plaintext) != "plaintext" {
+		t.Errorf("expected %q but got %q", "plaintext", plaintext)
+	}
+	envelope[len(envelope)-1] ^= 1
+	if _, err := Decrypt(key, envelope); err == nil {
+		t.Error("expected error for modified envelope")
+	}
+	if _, err := Decrypt(bytes.Repeat([]byte{2}, 16), envelope); err == nil {
+		t.Error("expected error for wrong key")
+	}
+	if _, err := Encrypt([]byte("short"), nil); err == nil {
+		t.Error("expected error for invalid key size")
+	}
+}
+
+// compress returns data compressed by the writer returned by fn.
+func compress(t *testing.T, data []byte, fn func(io.Writer) io.WriteCloser) []byte {
+	var buf bytes.Buffer
+	w := fn(&buf)
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
@@ -1,74 +1,321 @@
 // Package file provides an inject.Injector for file input.
 // The tag value is used as the filename.
-// Values of type *os.File will be set normally.
-// Other values will be depending on the file type.
+// Values of type *os.File, or of interface types it implements such as io.Reader and io.ReadCloser, will be set to the
+// open file, and *bufio.Scanner values will scan it. These files are closed with the Binder.
+// Values of type []byte will be set to the contents of the file.
+// Other values will be decoded depending on the file type, and the file closed.
 // File type is derived from the file extension, or optionally overridden by a tag option.
-// Supported types include: txt, json, xml, gob
+// Each file type has a Decoder. Built-in types include: txt, json, xml, gob, ini, properties, csv, toml
+// Text is parsed via literal.Injector, so values of other Kinds and registered types may be set from txt files.
+// Additional types may be supported by registering Decoders with RegisterDecoder.
+//
+// A fragment of a json, xml, ini, properties or toml file may be selected by a pointer following '#' in the filename,
+// like a JSON Pointer (RFC 6901), e.g. "config.json#/database/host". XML pointers start with the root element's name,
+// and may end with an attribute, e.g. "config.xml#/config/database/@host". Pointers into ini, properties and toml files
+// select sections and keys, e.g. "config.ini#/database/host". Files are parsed once per binding for all selections.
+//
+// A directory may be injected into a map with string keys by the 'dir' option, e.g. file:"conf.d/,dir" or
+// file:"conf.d/*.json,dir" to only include files matching a glob pattern. Keys are file names, and values are set from
+// the files like other values, except that string values are set to the file contents. Hidden files and
+// subdirectories are skipped, and a type option applies to all files.
+//
+// The integrity of files may be verified before they are read, by a sha256 option with the hex encoded SHA-256 digest
+// of the file, e.g. file:"model.gob,sha256=9f86...", or by a Manifest of digests. Mismatches fail with a
+// *ChecksumError.
+//
+// Missing files are errors, unless tagged with the 'optional' option, e.g. file:"local.json,optional", in which case
+// the value is not set so that other tag keys may set it.
+//
+// Configured injectors may be created with New, to search for files in multiple directories, or in an fs.FS such as an
+// embed.FS, and to restrict access to files within a root directory and below a maximum size.
+//
+// Compressed files with the extensions .gz, .bz2 and .zlib are decompressed, and encrypted files with the extension
+// .enc are decrypted with a key configured by KeyEnv or KeyFile (see Encrypt), before being decoded by the file type
+// preceding these extensions, e.g. "secrets.json.gz.enc".
 package file
 
 import (
-	"encoding/gob"
-	"encoding/json"
-	"encoding/xml"
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 
 	"github.com/go-modules/modules/inject"
 	"github.com/go-modules/modules/tags"
 )
 
-// Injector is an inject.Injector for file input.
-var Injector = inject.InjectorFunc(Inject)
+// Injector is an inject.FieldInjector for file input, relative to the working directory.
+var Injector = New()
 
-// Inject opens opens a file and sets the value via literal.Injector.
-func Inject(value reflect.Value, fileName string) (bool, error) {
-	if typeOfFile.AssignableTo(value.Type()) || typeOfFile.ConvertibleTo(value.Type()) {
-		file, err := os.Open(fileName)
+// Inject opens a file and sets the value from it.
+// Open files set to value are not tracked, so the caller is responsible for closing them.
+func Inject(value reflect.Value, tagValue string) (bool, error) {
+	return Injector.Inject(value, tagValue)
+}
+
+// InjectField opens a file and sets the value from it.
+// Open files set to value are tracked by ctx, to be closed with the Binder.
+func InjectField(ctx inject.InjectionContext, value reflect.Value, tagValue string) (bool, error) {
+	return Injector.InjectField(ctx, value, tagValue)
+}
+
+// New returns a new inject.FieldInjector for file input, configured with options.
+// Without options, it behaves like Injector.
+func New(options ...Option) inject.FieldInjector {
+	i := &injector{}
+	for _, option := range options {
+		option.configure(i)
+	}
+	if len(i.sources) == 0 {
+		i.sources = []source{dirSource(i.root)}
+	}
+	return i
+}
+
+// An injector opens files from its sources and implements inject.FieldInjector.
+type injector struct {
+	// Searched in order for files with relative names.
+	sources []source
+	// If not empty, files on disk must be within this directory.
+	root string
+	// If positive, the maximum file size in bytes.
+	maxSize int64
+	// Returns the key for decrypting .enc files. May be nil.
+	key func() ([]byte, error)
+	// If not empty, the checksum file of expected digests.
+	manifest string
+}
+
+// Inject opens a file and sets the value from it.
+// Open files set to value are not tracked, so the caller is responsible for closing them.
+func (i *injector) Inject(value reflect.Value, tagValue string) (bool, error) {
+	return i.InjectField(inject.InjectionContext{}, value, tagValue)
+}
+
+// InjectField opens a file and sets the value from it.
+// Open files set to value are tracked by ctx, to be closed with the Binder.
+func (i *injector) InjectField(ctx inject.InjectionContext, value reflect.Value, tagValue string) (bool, error) {
+	tag, err := parseTag(tagValue)
+	if err != nil {
+		return false, err
+	}
+	ok, err := i.inject(ctx, value, tag)
+	if tag.optional && errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return ok, err
+}
+
+// inject opens the file described by tag and sets the value from it.
+func (i *injector) inject(ctx inject.InjectionContext, value reflect.Value, tag fileTag) (bool, error) {
+	switch {
+	case tag.dir:
+		return i.injectDir(ctx, value, tag)
+	case tag.selected && (value.Type() == typeOfBytes || value.Type() == typeOfScanner || isHandle(value.Type())):
+		return false, fmt.Errorf("fragments may not be selected for values of type %s", value.Type())
+	case value.Type() == typeOfBytes:
+		r, err := i.openDecoded(tag)
 		if err != nil {
 			return false, err
 		}
-		value.Elem().Set(reflect.ValueOf(file).Elem())
-		return true, nil
-	} else {
-		tag, optionalType := tags.ParseTag(fileName)
-		file, err := os.Open(tag)
+		defer r.Close()
+		bytes, err := ioutil.ReadAll(r)
 		if err != nil {
 			return false, err
 		}
-		fileType := string(optionalType)
-		if fileType == "" {
-			fileType = filepath.Ext(tag)[1:]
+		return true, set(value, reflect.ValueOf(bytes))
+	case value.Type() == typeOfScanner:
+		return i.setOpen(ctx, value, tag, func(r io.Reader) reflect.Value {
+			return reflect.ValueOf(bufio.NewScanner(r))
+		})
+	case isHandle(value.Type()):
+		return i.setOpen(ctx, value, tag, func(r io.Reader) reflect.Value {
+			return reflect.ValueOf(r)
+		})
+	}
+
+	if tag.fileType == "" {
+		return false, fmt.Errorf("no extension or type option given for file: %s", tag.name)
+	}
+	if tag.selected {
+		tokens, err := parsePointer(tag.pointer)
+		if err != nil {
+			return false, err
 		}
-		if fileType == "" {
-			return false, fmt.Errorf("no extension or type option given for file: %s", tag)
+		doc, err := i.loadDocument(ctx, tag)
+		if err != nil {
+			return false, err
 		}
-		switch fileType {
-		case "txt":
-			if bytes, err := ioutil.ReadAll(file); err != nil {
-				return false, err
-			} else {
-				value.SetString(string(bytes))
-			}
-		case "json":
-			if err := json.NewDecoder(file).Decode(value.Interface()); err != nil {
-				return false, err
-			}
-		case "xml":
-			if err := xml.NewDecoder(file).Decode(value.Interface()); err != nil {
-				return false, err
+		if err := doc.decode(tokens, value); err != nil {
+			return false, fmt.Errorf("unable to read file %s: %s", tag.name, err)
+		}
+		return true, nil
+	}
+
+	r, err := i.openDecoded(tag)
+	if err != nil {
+		return false, err
+	}
+	defer r.Close()
+	if err := Decode(r, tag.fileType, value); err != nil {
+		return false, fmt.Errorf("unable to read file %s: %s", tag.name, err)
+	}
+	return true, nil
+}
+
+// loadDocument parses the file described by tag as a document of its file type, or returns the document cached by ctx.
+func (i *injector) loadDocument(ctx inject.InjectionContext, tag fileTag) (document, error) {
+	expected, err := i.expectedDigest(tag)
+	if err != nil {
+		return nil, err
+	}
+	key := documentKey{i, tag.name, tag.fileType, expected}
+	if ctx.Cache != nil {
+		if doc, ok := ctx.Cache.Load(key); ok {
+			return doc.(document), nil
+		}
+	}
+	r, err := i.openDecoded(tag)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	doc, err := parseDocument(r, tag.fileType)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read file %s: %s", tag.name, err)
+	}
+	if ctx.Cache != nil {
+		ctx.Cache.Store(key, doc)
+	}
+	return doc, nil
+}
+
+// setOpen opens the file described by tag and sets value to the result of fn, then tracks the file with ctx.
+// Compressed or encrypted files are passed to fn as a reader of the decoded contents, so cannot be set to *os.File
+// values. The file is closed if value cannot be set.
+func (i *injector) setOpen(ctx inject.InjectionContext, value reflect.Value, tag fileTag, fn func(io.Reader) reflect.Value) (bool, error) {
+	var r io.ReadCloser
+	var err error
+	if len(tag.encodings) == 0 {
+		var file fs.File
+		if file, err = i.open(tag.name); err == nil {
+			if err = i.verifyFile(tag, file); err != nil {
+				file.Close()
 			}
-		case "gob":
-			if err := gob.NewDecoder(file).Decode(value.Interface()); err != nil {
-				return false, err
+		}
+		r = file
+	} else {
+		r, err = i.openDecoded(tag)
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := set(value, fn(r)); err != nil {
+		r.Close()
+		return false, err
+	}
+	ctx.Track(r)
+	return true, nil
+}
+
+// A fileTag holds a parsed file tag value.
+type fileTag struct {
+	// The file name.
+	name string
+	// The fragment pointer, if selected.
+	pointer  string
+	selected bool
+	// The file type, from the type option or else the file extension.
+	fileType string
+	// The compression and encryption extensions following the file type's, outermost first.
+	encodings []string
+	// Whether a missing file is permitted.
+	optional bool
+	// Whether name is a directory, optionally ending with a glob pattern, to inject as a map of its files.
+	dir bool
+	// The expected hex encoded SHA-256 digest of the file, if any.
+	sha256 string
+}
+
+// parseTag parses a tag value of the form name[#pointer][,type][,optional][,dir][,sha256=digest].
+func parseTag(tagValue string) (fileTag, error) {
+	name, options := tags.ParseTag(tagValue)
+	var tag fileTag
+	tag.name, tag.pointer, tag.selected = strings.Cut(name, "#")
+	if options != "" {
+		for _, option := range strings.Split(string(options), ",") {
+			switch {
+			case option == "optional":
+				tag.optional = true
+			case option == "dir":
+				tag.dir = true
+			case strings.HasPrefix(option, "sha256="):
+				digest, err := parseDigest(strings.TrimPrefix(option, "sha256="))
+				if err != nil {
+					return fileTag{}, fmt.Errorf("%s in file tag value %q", err, tagValue)
+				}
+				tag.sha256 = digest
+			case tag.fileType == "":
+				tag.fileType = option
+			default:
+				return fileTag{}, fmt.Errorf("unexpected option %q in file tag value %q", option, tagValue)
 			}
-		default:
-			return false, fmt.Errorf("unable to read file %s, unrecognized file type %s", tag, fileType)
 		}
-		return true, nil
+	}
+	if tag.dir && tag.selected {
+		return fileTag{}, fmt.Errorf("fragments may not be selected from directories in file tag value %q", tagValue)
+	}
+	if tag.dir && tag.sha256 != "" {
+		return fileTag{}, fmt.Errorf("the sha256 option may not be used with directories in file tag value %q", tagValue)
+	}
+	if !tag.dir {
+		tag.deriveType()
+	}
+	return tag, nil
+}
+
+// deriveType sets t's encodings from the extensions of compressed and encrypted files, and then its file type from the
+// preceding extension, unless already set.
+func (t *fileTag) deriveType() {
+	base := t.name
+	for isEncoding(strings.TrimPrefix(filepath.Ext(base), ".")) {
+		t.encodings = append(t.encodings, strings.TrimPrefix(filepath.Ext(base), "."))
+		base = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	if t.fileType == "" {
+		t.fileType = strings.TrimPrefix(filepath.Ext(base), ".")
 	}
 }
 
-var typeOfFile = reflect.TypeOf(new(os.File))
+// set sets value to v, or if value is not settable but is a non-nil pointer, sets the value it points to.
+func set(value, v reflect.Value) error {
+	if !v.Type().AssignableTo(value.Type()) {
+		return fmt.Errorf("cannot set value of type %s to %s", value.Type(), v.Type())
+	}
+	if value.CanSet() {
+		value.Set(v)
+		return nil
+	}
+	if value.Kind() == reflect.Ptr && !value.IsNil() && v.Kind() == reflect.Ptr {
+		value.Elem().Set(v.Elem())
+		return nil
+	}
+	return fmt.Errorf("cannot set value of type %s", value.Type())
+}
+
+// isHandle returns true if typ is *os.File, or a non-empty interface implemented by *os.File.
+func isHandle(typ reflect.Type) bool {
+	return typ == typeOfFile || typ.Kind() == reflect.Interface && typ.NumMethod() > 0 && typeOfFile.Implements(typ)
+}
+
+var (
+	typeOfFile    = reflect.TypeOf(new(os.File))
+	typeOfBytes   = reflect.TypeOf([]byte(nil))
+	typeOfScanner = reflect.TypeOf(new(bufio.Scanner))
+)
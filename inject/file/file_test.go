@@ -1,11 +1,15 @@
 package file
 
 import (
+	"bufio"
 	"encoding/xml"
+	"io"
 	"io/ioutil"
 	"os"
 	"reflect"
 	"testing"
+
+	"github.com/go-modules/modules/inject"
 )
 
 type JsonType struct {
@@ -77,3 +81,67 @@ func TestInjector(t *testing.T) {
 		t.Errorf(`file: expected "test" but got %q`, string(bytes))
 	}
 }
+
+func TestInjectField(t *testing.T) {
+	var tracked []io.Closer
+	ctx := inject.InjectionContext{OnClose: func(c io.Closer) {
+		tracked = append(tracked, c)
+	}}
+
+	var bytes []byte
+	if ok, err := InjectField(ctx, reflect.ValueOf(&bytes).Elem(), "test.txt"); err != nil || !ok {
+		t.Fatalf("expected bytes to be set: %v", err)
+	} else if string(bytes) != "test" {
+		t.Errorf(`bytes: expected "test" but got %q`, string(bytes))
+	}
+
+	var reader io.Reader
+	if ok, err := InjectField(ctx, reflect.ValueOf(&reader).Elem(), "test.txt"); err != nil || !ok {
+		t.Fatalf("expected reader to be set: %v", err)
+	} else if bytes, err := ioutil.ReadAll(reader); err != nil {
+		t.Error(err)
+	} else if string(bytes) != "test" {
+		t.Errorf(`reader: expected "test" but got %q`, string(bytes))
+	}
+
+	var scanner *bufio.Scanner
+	if ok, err := InjectField(ctx, reflect.ValueOf(&scanner).Elem(), "test.txt"); err != nil || !ok {
+		t.Fatalf("expected scanner to be set: %v", err)
+	} else if !scanner.Scan() || scanner.Text() != "test" {
+		t.Errorf(`scanner: expected "test" but got %q`, scanner.Text())
+	}
+
+	var file *os.File
+	if ok, err := InjectField(ctx, reflect.ValueOf(&file).Elem(), "test.txt"); err != nil || !ok {
+		t.Fatalf("expected file to be set: %v", err)
+	}
+
+	var decoded JsonType
+	if ok, err := InjectField(ctx, reflect.ValueOf(&decoded).Elem(), "test.json"); err != nil || !ok {
+		t.Fatalf("expected decoded value to be set: %v", err)
+	}
+
+	// Only the open files set to values are tracked.
+	if len(tracked) != 3 {
+		t.Fatalf("expected 3 tracked files but got %d", len(tracked))
+	}
+	if tracked[2] != file {
+		t.Error("expected *os.File value to be tracked")
+	}
+	for _, c := range tracked {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if _, err := InjectField(ctx, reflect.ValueOf(file), "test.txt"); err != nil {
+		t.Error(err)
+	}
+	if _, err := InjectField(ctx, reflect.ValueOf(bytes), "test.txt"); err == nil {
+		t.Error("expected error setting unsettable value")
+	}
+	if len(tracked) != 4 {
+		t.Errorf("expected 4 tracked files but got %d", len(tracked))
+	}
+	tracked[3].Close()
+}
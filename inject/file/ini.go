@@ -0,0 +1,44 @@
+package file
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// decodeINI decodes INI files into maps or structs, via assign with the 'ini' tag key.
+// Keys before the first section are top level, and keys within a [section] are nested within it. Keys and values are
+// separated by '=' or ':'. Lines starting with ';' or '#' are comments, and values may be double quoted.
+func decodeINI(r io.Reader, v interface{}) error {
+	target := reflect.ValueOf(v).Elem()
+	scanner := bufio.NewScanner(r)
+	var section []string
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == ';' || text[0] == '#' {
+			continue
+		}
+		if text[0] == '[' {
+			if text[len(text)-1] != ']' {
+				return fmt.Errorf("line %d: malformed section %q", line, text)
+			}
+			section = strings.Split(strings.TrimSpace(text[1:len(text)-1]), ".")
+			continue
+		}
+		i := strings.IndexAny(text, "=:")
+		if i < 0 {
+			return fmt.Errorf("line %d: expected key=value but found %q", line, text)
+		}
+		key, value := strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:])
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = value[1 : len(value)-1]
+		}
+		path := append(append([]string(nil), section...), key)
+		if err := assign(target, path, value, "ini"); err != nil {
+			return fmt.Errorf("line %d: %s", line, err)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,115 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// Manifest is an Option naming a checksum file in the format of sha256sum output: lines of a hex encoded SHA-256
+// digest and a file name, separated by whitespace. Listed files are verified before being read, and are matched by the
+// file name in the tag value (joined with the directory for the 'dir' option). Other files are not verified.
+type Manifest string
+
+func (m Manifest) configure(i *injector) {
+	i.manifest = string(m)
+}
+
+// A ChecksumError indicates that the contents of a file do not match its expected SHA-256 digest.
+type ChecksumError struct {
+	// The file name.
+	File string
+	// The hex encoded expected and actual digests.
+	Expected, Actual string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("checksum mismatch for file %s: expected sha256 %s but got %s", e.File, e.Expected, e.Actual)
+}
+
+// expectedDigest returns the expected digest of the file described by tag, from its sha256 option or else i's
+// manifest, or "" if there is none.
+func (i *injector) expectedDigest(tag fileTag) (string, error) {
+	if tag.sha256 != "" || i.manifest == "" {
+		return tag.sha256, nil
+	}
+	digests, err := readManifest(i.manifest)
+	if err != nil {
+		return "", err
+	}
+	return digests[filepath.Clean(tag.name)], nil
+}
+
+// readManifest reads the digests in a checksum file, by cleaned file name.
+func readManifest(path string) (map[string]string, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read manifest: %s", err)
+	}
+	digests := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+		i := strings.IndexAny(text, " \t")
+		if i < 0 {
+			return nil, fmt.Errorf("manifest %s line %d: expected digest and file name", path, line)
+		}
+		digest, err := parseDigest(text[:i])
+		if err != nil {
+			return nil, fmt.Errorf("manifest %s line %d: %s", path, line, err)
+		}
+		// sha256sum marks files read in binary mode with '*'.
+		name := strings.TrimPrefix(strings.TrimLeft(text[i:], " \t"), "*")
+		digests[filepath.Clean(name)] = digest
+	}
+	return digests, scanner.Err()
+}
+
+// parseDigest validates and lower cases a hex encoded SHA-256 digest.
+func parseDigest(digest string) (string, error) {
+	if b, err := hex.DecodeString(digest); err != nil || len(b) != sha256.Size {
+		return "", fmt.Errorf("invalid sha256 digest %q", digest)
+	}
+	return strings.ToLower(digest), nil
+}
+
+// verify reads the contents of r, and returns them if their digest matches expected, or else a *ChecksumError.
+func verify(name string, r io.Reader, expected string) ([]byte, error) {
+	contents, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(contents)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return nil, &ChecksumError{File: name, Expected: expected, Actual: actual}
+	}
+	return contents, nil
+}
+
+// verifyFile verifies the contents of file against the expected digest of tag, if any, and then seeks back to the
+// start of file.
+func (i *injector) verifyFile(tag fileTag, file fs.File) error {
+	expected, err := i.expectedDigest(tag)
+	if err != nil || expected == "" {
+		return err
+	}
+	seeker, ok := file.(io.Seeker)
+	if !ok {
+		return fmt.Errorf("unable to verify file %s: not seekable", tag.name)
+	}
+	if _, err := verify(tag.name, i.reader(tag.name, file), expected); err != nil {
+		return err
+	}
+	_, err = seeker.Seek(0, io.SeekStart)
+	return err
+}
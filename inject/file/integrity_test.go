@@ -0,0 +1,129 @@
+package file
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/go-modules/modules/inject"
+)
+
+func TestChecksum(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	contents := []byte(`{"test": "test"}`)
+	sum := sha256.Sum256(contents)
+	digest := hex.EncodeToString(sum[:])
+	wrong := hex.EncodeToString(make([]byte, sha256.Size))
+	path := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(path, contents, 0600); err != nil {
+		t.Fatal(err)
+	}
+	manifest := filepath.Join(dir, "SHA256SUMS")
+	if err := ioutil.WriteFile(manifest, []byte("# checksums\n"+digest+"  "+path+"\n"+wrong+" *"+filepath.Join(dir, "other.json")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "other.json"), contents, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var value JsonType
+	if _, err := Inject(reflect.ValueOf(&value).Elem(), path+",sha256="+digest); err != nil {
+		t.Error(err)
+	} else if value.Test != "test" {
+		t.Errorf("expected value to be decoded, but got %+v", value)
+	}
+	var file *os.File
+	if _, err := Inject(reflect.ValueOf(&file).Elem(), path+",sha256="+digest); err != nil {
+		t.Error(err)
+	} else if read, err := ioutil.ReadAll(file); err != nil || string(read) != string(contents) {
+		t.Errorf("expected file to be read from the start, but got %q (%v)", read, err)
+	} else {
+		file.Close()
+	}
+
+	for _, tagValue := range []string{
+		path + ",sha256=" + wrong,
+		path + "#/test,sha256=" + wrong,
+	} {
+		var value interface{}
+		_, err := Inject(reflect.ValueOf(&value).Elem(), tagValue)
+		var checksumErr *ChecksumError
+		if !errors.As(err, &checksumErr) {
+			t.Errorf("%s: expected *ChecksumError but got %v", tagValue, err)
+		} else if checksumErr.File != path || checksumErr.Expected != wrong || checksumErr.Actual != digest {
+			t.Errorf("%s: unexpected error: %+v", tagValue, checksumErr)
+		}
+	}
+	for _, tagValue := range []string{
+		path + ",sha256=123",
+		path + ",sha256=" + wrong[1:] + "x",
+		dir + ",dir,sha256=" + digest,
+	} {
+		if _, err := Inject(reflect.ValueOf(&value).Elem(), tagValue); err == nil {
+			t.Errorf("%s: expected error", tagValue)
+		}
+	}
+
+	injector := New(Manifest(manifest))
+	if _, err := injector.Inject(reflect.ValueOf(&value).Elem(), path); err != nil {
+		t.Error(err)
+	}
+	var checksumErr *ChecksumError
+	if _, err := injector.Inject(reflect.ValueOf(&value).Elem(), filepath.Join(dir, "other.json")); !errors.As(err, &checksumErr) {
+		t.Errorf("expected *ChecksumError but got %v", err)
+	}
+	var files map[string]JsonType
+	if _, err := injector.Inject(reflect.ValueOf(&files).Elem(), dir+"/*.json,dir"); !errors.As(err, &checksumErr) {
+		t.Errorf("expected *ChecksumError but got %v", err)
+	}
+	// The sha256 option takes precedence over the manifest.
+	if _, err := injector.Inject(reflect.ValueOf(&value).Elem(), filepath.Join(dir, "other.json")+",sha256="+digest); err != nil {
+		t.Error(err)
+	}
+	if _, err := New(Manifest(filepath.Join(dir, "missing"))).Inject(reflect.ValueOf(&value).Elem(), path); err == nil {
+		t.Error("expected error for missing manifest")
+	}
+}
+
+func TestChecksumCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	contents := []byte(`{"a": "x", "b": "y"}`)
+	sum := sha256.Sum256(contents)
+	digest := hex.EncodeToString(sum[:])
+	wrong := hex.EncodeToString(make([]byte, sha256.Size))
+	path := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(path, contents, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Documents cached without verification are not selected from for tags with digests.
+	ctx := inject.InjectionContext{Cache: &sync.Map{}}
+	var s string
+	if _, err := InjectField(ctx, reflect.ValueOf(&s).Elem(), path+"#/a"); err != nil || s != "x" {
+		t.Fatalf("expected %q but got %q (%v)", "x", s, err)
+	}
+	s = ""
+	var checksumErr *ChecksumError
+	if _, err := InjectField(ctx, reflect.ValueOf(&s).Elem(), path+"#/b,sha256="+wrong); !errors.As(err, &checksumErr) {
+		t.Errorf("expected *ChecksumError but got %v", err)
+	} else if s != "" {
+		t.Errorf("expected value not to be set, but got %q", s)
+	}
+	if _, err := InjectField(ctx, reflect.ValueOf(&s).Elem(), path+"#/b,sha256="+digest); err != nil || s != "y" {
+		t.Errorf("expected %q but got %q (%v)", "y", s, err)
+	}
+}
@@ -0,0 +1,106 @@
+package file
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// decodeProperties decodes Java properties files into maps or structs, via assign with the 'properties' tag key.
+// Keys are separated from values by '=', ':' or whitespace, and dotted keys are nested. Lines starting with '#' or
+// '!' are comments, lines ending with an odd number of backslashes continue on the next line, and the escapes \t,
+// \n, \r, \f and \uXXXX are supported.
+func decodeProperties(r io.Reader, v interface{}) error {
+	target := reflect.ValueOf(v).Elem()
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimLeft(scanner.Text(), " \t\f")
+		if text == "" || text[0] == '#' || text[0] == '!' {
+			continue
+		}
+		start := line
+		for continues(text) && scanner.Scan() {
+			line++
+			text = text[:len(text)-1] + strings.TrimLeft(scanner.Text(), " \t\f")
+		}
+
+		// Find the unescaped separator.
+		end := len(text)
+		for i := 0; i < len(text); i++ {
+			if text[i] == '\\' {
+				i++
+			} else if strings.IndexByte("=: \t\f", text[i]) >= 0 {
+				end = i
+				break
+			}
+		}
+		key, value := text[:end], strings.TrimLeft(text[end:], " \t\f")
+		if value != "" && (value[0] == '=' || value[0] == ':') {
+			value = strings.TrimLeft(value[1:], " \t\f")
+		}
+
+		key, err := unescapeProperty(key)
+		if err != nil {
+			return fmt.Errorf("line %d: %s", start, err)
+		}
+		if value, err = unescapeProperty(value); err != nil {
+			return fmt.Errorf("line %d: %s", start, err)
+		}
+		if err := assign(target, strings.Split(key, "."), value, "properties"); err != nil {
+			return fmt.Errorf("line %d: %s", start, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// continues returns true if line ends with an odd number of backslashes.
+func continues(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// unescapeProperty replaces escape sequences in s.
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, "\\") {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("malformed unicode escape in %q", s)
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("malformed unicode escape in %q", s)
+			}
+			b.WriteRune(rune(r))
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,19 @@
+//go:build go1.24
+
+package file
+
+import (
+	"io/fs"
+	"os"
+)
+
+// openInRoot opens the file at path rel within the directory root.
+// os.Root rejects symbolic links which escape the root.
+func openInRoot(root, rel string) (fs.File, error) {
+	r, err := os.OpenRoot(root)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return r.Open(rel)
+}
@@ -0,0 +1,30 @@
+//go:build !go1.24
+
+package file
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// openInRoot opens the file at path rel within the directory root.
+// Without os.Root (Go 1.24), symbolic links are resolved before opening, and rejected if they escape the root. Unlike
+// os.Root, this does not guard against links changing between resolving and opening.
+func openInRoot(root, rel string) (fs.File, error) {
+	path := filepath.Join(root, rel)
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil, err
+	}
+	resolvedRel, err := filepath.Rel(resolvedRoot, resolved)
+	if err != nil || resolvedRel == ".." || strings.HasPrefix(resolvedRel, ".."+string(filepath.Separator)) {
+		return nil, &fs.PathError{Op: "open", Path: path, Err: ErrOutsideRoot}
+	}
+	return os.Open(resolved)
+}
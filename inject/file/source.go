@@ -0,0 +1,184 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// An Option configures an injector created by New.
+type Option interface {
+	configure(*injector)
+}
+
+// SearchPath is an Option which adds directories to search, in order, for files with relative names,
+// e.g. file.SearchPath{"./config", filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "app"), "/etc/app"}.
+type SearchPath []string
+
+func (s SearchPath) configure(i *injector) {
+	for _, dir := range s {
+		i.sources = append(i.sources, dirSource(dir))
+	}
+}
+
+// FS is an Option which adds a file system to search for files with relative names, after any previous Options, e.g.
+// to fall back to defaults in an embed.FS after searching on-disk overrides.
+// Files opened from an fs.FS may not be set to *os.File values.
+type FS struct {
+	fs.FS
+}
+
+func (f FS) configure(i *injector) {
+	i.sources = append(i.sources, fsSource{f.FS})
+}
+
+// Root is an Option which confines files opened from disk to a directory, including search paths and absolute names,
+// which default to the root directory itself. Symbolic links may not escape the root, and must be relative when built
+// with Go 1.24 or later, which enforces the root with os.Root.
+type Root string
+
+func (r Root) configure(i *injector) {
+	i.root = string(r)
+}
+
+// MaxSize is an Option which limits the size of files in bytes. Files which are larger when opened are rejected, and
+// reading more than MaxSize bytes from files being decoded fails.
+type MaxSize int64
+
+func (m MaxSize) configure(i *injector) {
+	i.maxSize = int64(m)
+}
+
+// ErrOutsideRoot is returned, wrapped in an *fs.PathError, for paths outside of an injector's Root.
+var ErrOutsideRoot = errors.New("path is outside of the file root")
+
+// ErrTooLarge is returned, wrapped, for files larger than an injector's MaxSize.
+var ErrTooLarge = errors.New("file exceeds maximum size")
+
+// A source opens files by relative name.
+type source interface {
+	open(i *injector, name string) (fs.File, error)
+	String() string
+}
+
+// A dirSource opens files relative to a directory, or the working directory if empty.
+type dirSource string
+
+func (d dirSource) open(i *injector, name string) (fs.File, error) {
+	return i.openPath(filepath.Join(string(d), name))
+}
+
+func (d dirSource) String() string {
+	if d == "" {
+		return "."
+	}
+	return string(d)
+}
+
+// An fsSource opens files from an fs.FS.
+type fsSource struct {
+	fs.FS
+}
+
+func (f fsSource) open(_ *injector, name string) (fs.File, error) {
+	name = path.Clean(filepath.ToSlash(name))
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return f.FS.Open(name)
+}
+
+func (f fsSource) String() string {
+	return fmt.Sprintf("%T", f.FS)
+}
+
+// open opens the file with name from the first of i's sources which has it, and checks its size.
+// Returns an error satisfying errors.Is(err, fs.ErrNotExist) if no source has the file.
+func (i *injector) open(name string) (fs.File, error) {
+	file, err := i.find(name)
+	if err != nil || i.maxSize <= 0 {
+		return file, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.Size() > i.maxSize {
+		file.Close()
+		return nil, fmt.Errorf("file %s of %d bytes: %w of %d bytes", name, info.Size(), ErrTooLarge, i.maxSize)
+	}
+	return file, nil
+}
+
+// reader returns a reader for the contents of r, which fails after reading more than i's MaxSize bytes.
+func (i *injector) reader(name string, r io.Reader) io.Reader {
+	if i.maxSize <= 0 {
+		return r
+	}
+	return &limitedReader{r, i.maxSize, name}
+}
+
+// find opens the file with name from the first of i's sources which has it, or opens absolute names directly.
+func (i *injector) find(name string) (fs.File, error) {
+	if filepath.IsAbs(name) {
+		return i.openPath(name)
+	}
+	if len(i.sources) == 1 {
+		return i.sources[0].open(i, name)
+	}
+	searched := make([]string, len(i.sources))
+	for j, source := range i.sources {
+		file, err := source.open(i, name)
+		if err == nil {
+			return file, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		searched[j] = source.String()
+	}
+	return nil, fmt.Errorf("file %s not found in %s: %w", name, strings.Join(searched, ", "), fs.ErrNotExist)
+}
+
+// openPath opens the file at path on disk, within i's root if configured.
+func (i *injector) openPath(path string) (fs.File, error) {
+	if i.root == "" {
+		return os.Open(path)
+	}
+	root, err := filepath.Abs(i.root)
+	if err != nil {
+		return nil, err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, &fs.PathError{Op: "open", Path: path, Err: ErrOutsideRoot}
+	}
+	return openInRoot(root, rel)
+}
+
+// A limitedReader reads from r, and fails with ErrTooLarge after reading more than n bytes.
+type limitedReader struct {
+	r    io.Reader
+	n    int64
+	name string
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	if l.n -= int64(n); l.n < 0 {
+		return 0, fmt.Errorf("file %s: %w", l.name, ErrTooLarge)
+	}
+	return n, err
+}
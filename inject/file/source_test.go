@@ -0,0 +1,180 @@
+package file
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-modules/modules/inject"
+)
+
+func TestNew(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	local, global := filepath.Join(dir, "local"), filepath.Join(dir, "global")
+	for path, contents := range map[string]string{
+		filepath.Join(local, "a.txt"):  "local a",
+		filepath.Join(global, "a.txt"): "global a",
+		filepath.Join(global, "b.txt"): "global b",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defaults := fstest.MapFS{
+		"a.txt":             {Data: []byte("default a")},
+		"c.txt":             {Data: []byte("default c")},
+		"config/nested.txt": {Data: []byte("default nested")},
+	}
+
+	injector := New(SearchPath{local, global}, FS{defaults})
+	for tagValue, expected := range map[string]string{
+		"a.txt":                        "local a",
+		"b.txt":                        "global b",
+		"c.txt":                        "default c",
+		"config/nested.txt":            "default nested",
+		filepath.Join(global, "a.txt"): "global a",
+	} {
+		var s string
+		if ok, err := injector.Inject(reflect.ValueOf(&s).Elem(), tagValue); err != nil {
+			t.Errorf("%s: %s", tagValue, err)
+		} else if !ok {
+			t.Errorf("%s: expected value to be set", tagValue)
+		} else if s != expected {
+			t.Errorf("%s: expected %q but got %q", tagValue, expected, s)
+		}
+	}
+
+	var s string
+	if _, err := injector.Inject(reflect.ValueOf(&s).Elem(), "d.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected not exist error, but got %v", err)
+	}
+	if _, err := New(FS{defaults}).Inject(reflect.ValueOf(&s).Elem(), "../c.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected not exist error, but got %v", err)
+	}
+	if ok, err := injector.Inject(reflect.ValueOf(&s).Elem(), "d.txt,optional"); err != nil || ok {
+		t.Errorf("expected (false, nil) for optional missing file, but got (%t, %v)", ok, err)
+	}
+	if _, err := injector.Inject(reflect.ValueOf(&s).Elem(), "d.txt,txt,optional,extra"); err == nil {
+		t.Error("expected error for unexpected option")
+	}
+
+	var reader io.Reader
+	if ok, err := injector.Inject(reflect.ValueOf(&reader).Elem(), "c.txt"); err != nil || !ok {
+		t.Fatalf("expected reader to be set: %v", err)
+	} else if bytes, err := ioutil.ReadAll(reader); err != nil || string(bytes) != "default c" {
+		t.Errorf("expected %q but got %q (%v)", "default c", string(bytes), err)
+	}
+	var file *os.File
+	if _, err := injector.Inject(reflect.ValueOf(&file).Elem(), "c.txt"); err == nil {
+		t.Error("expected error setting *os.File from fs.FS")
+	}
+}
+
+func TestOptional(t *testing.T) {
+	var s string
+	if ok, err := Inject(reflect.ValueOf(&s).Elem(), "missing.txt,optional"); err != nil || ok {
+		t.Errorf("expected (false, nil) for optional missing file, but got (%t, %v)", ok, err)
+	}
+	if ok, err := Inject(reflect.ValueOf(&s).Elem(), "missing.json#/a,optional"); err != nil || ok {
+		t.Errorf("expected (false, nil) for optional missing file, but got (%t, %v)", ok, err)
+	}
+	if _, err := Inject(reflect.ValueOf(&s).Elem(), "missing.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected not exist error, but got %v", err)
+	}
+	if ok, err := Inject(reflect.ValueOf(&s).Elem(), "test.txt,optional"); err != nil || !ok || s != "test" {
+		t.Errorf("expected optional existing file to be set, but got (%t, %v)", ok, err)
+	}
+}
+
+func TestRoot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	root := filepath.Join(dir, "root")
+	if err := os.MkdirAll(filepath.Join(root, "config"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	for path, contents := range map[string]string{
+		filepath.Join(root, "config", "a.txt"): "a",
+		filepath.Join(root, "big.txt"):         "0123456789",
+		filepath.Join(dir, "secret.txt"):       "secret",
+	} {
+		if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(dir, "secret.txt"), filepath.Join(root, "link.txt")); err != nil {
+		t.Skip("symbolic links not supported:", err)
+	}
+	if err := os.Symlink(filepath.Join("config", "a.txt"), filepath.Join(root, "inner.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	injector := New(Root(root), MaxSize(5))
+	searching := New(Root(root), SearchPath{filepath.Join(root, "config"), dir})
+	for _, testCase := range []struct {
+		injector inject.FieldInjector
+		tagValue string
+		expected string
+	}{
+		{injector, "config/a.txt", "a"},
+		{injector, "inner.txt", "a"},
+		{injector, filepath.Join(root, "config", "a.txt"), "a"},
+		{searching, "a.txt", "a"},
+	} {
+		var s string
+		if ok, err := testCase.injector.Inject(reflect.ValueOf(&s).Elem(), testCase.tagValue); err != nil {
+			t.Errorf("%s: %s", testCase.tagValue, err)
+		} else if !ok || s != testCase.expected {
+			t.Errorf("%s: expected %q but got %q", testCase.tagValue, testCase.expected, s)
+		}
+	}
+
+	for _, testCase := range []struct {
+		injector inject.FieldInjector
+		tagValue string
+		expected error
+	}{
+		{injector, "../secret.txt", ErrOutsideRoot},
+		{injector, "../secret.txt,optional", ErrOutsideRoot},
+		{injector, filepath.Join(dir, "secret.txt"), ErrOutsideRoot},
+		{searching, "secret.txt", ErrOutsideRoot},
+		{injector, "big.txt", ErrTooLarge},
+	} {
+		var s string
+		if _, err := testCase.injector.Inject(reflect.ValueOf(&s).Elem(), testCase.tagValue); !errors.Is(err, testCase.expected) {
+			t.Errorf("%s: expected %v but got %v", testCase.tagValue, testCase.expected, err)
+		}
+	}
+	var s string
+	if _, err := injector.Inject(reflect.ValueOf(&s).Elem(), "link.txt"); err == nil || s == "secret" {
+		t.Errorf("expected error following symbolic link out of root, but got %q (%v)", s, err)
+	}
+}
+
+func TestMaxSize(t *testing.T) {
+	r := &limitedReader{strings.NewReader("0123456789"), 10, "test"}
+	if bytes, err := ioutil.ReadAll(r); err != nil || string(bytes) != "0123456789" {
+		t.Errorf("expected to read 10 bytes, but got %q (%v)", string(bytes), err)
+	}
+	r = &limitedReader{strings.NewReader("0123456789"), 9, "test"}
+	if _, err := ioutil.ReadAll(r); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected %v but got %v", ErrTooLarge, err)
+	}
+}
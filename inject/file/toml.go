@@ -0,0 +1,259 @@
+package file
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// decodeTOML decodes a subset of TOML into maps or structs, via assign with the 'toml' tag key.
+// Supported: comments, [table] and [dotted.table] headers, bare, quoted and dotted keys, basic and literal strings,
+// integers, floats, booleans and (possibly multiline) arrays of these values.
+// Not supported: multiline strings, inline tables, arrays of tables and dates.
+func decodeTOML(r io.Reader, v interface{}) error {
+	bytes, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	p := &tomlParser{src: string(bytes), line: 1}
+	target := reflect.ValueOf(v).Elem()
+	var table []string
+	for {
+		p.skip(true)
+		if p.eof() {
+			return nil
+		}
+		if p.peek() == '[' {
+			p.pos++
+			if !p.eof() && p.peek() == '[' {
+				return p.errorf("arrays of tables are not supported")
+			}
+			if table, err = p.key(); err != nil {
+				return err
+			}
+			if p.skip(false); p.eof() || p.peek() != ']' {
+				return p.errorf("expected ']' after table name")
+			}
+			p.pos++
+		} else {
+			key, err := p.key()
+			if err != nil {
+				return err
+			}
+			if p.skip(false); p.eof() || p.peek() != '=' {
+				return p.errorf("expected '=' after key")
+			}
+			p.pos++
+			p.skip(false)
+			value, err := p.value()
+			if err != nil {
+				return err
+			}
+			path := append(append([]string(nil), table...), key...)
+			if err := assign(target, path, value, "toml"); err != nil {
+				return p.errorf("%s: %s", strings.Join(path, "."), err)
+			}
+		}
+		if p.skip(false); !p.eof() && p.peek() != '\n' {
+			return p.errorf("unexpected %q at end of line", p.peek())
+		}
+	}
+}
+
+// A tomlParser holds the state of a TOML document being parsed.
+type tomlParser struct {
+	src  string
+	pos  int
+	line int
+}
+
+func (p *tomlParser) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, a...))
+}
+
+func (p *tomlParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *tomlParser) peek() byte {
+	return p.src[p.pos]
+}
+
+// skip skips whitespace and comments, and also newlines if newlines is true.
+func (p *tomlParser) skip(newlines bool) {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '\n' && newlines:
+			p.pos++
+			p.line++
+		case c == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// key parses a bare, quoted or dotted key.
+func (p *tomlParser) key() ([]string, error) {
+	var path []string
+	for {
+		p.skip(false)
+		if p.eof() {
+			return nil, p.errorf("expected key")
+		}
+		var part string
+		switch p.peek() {
+		case '"', '\'':
+			s, err := p.str()
+			if err != nil {
+				return nil, err
+			}
+			part = s
+		default:
+			start := p.pos
+			for !p.eof() && isBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("expected key but found %q", p.peek())
+			}
+			part = p.src[start:p.pos]
+		}
+		path = append(path, part)
+		if p.skip(false); p.eof() || p.peek() != '.' {
+			return path, nil
+		}
+		p.pos++
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c == '_' || c == '-' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// value parses a string, number, boolean or array.
+func (p *tomlParser) value() (interface{}, error) {
+	if p.eof() {
+		return nil, p.errorf("expected value")
+	}
+	switch c := p.peek(); {
+	case c == '"' || c == '\'':
+		if strings.HasPrefix(p.src[p.pos:], `"""`) || strings.HasPrefix(p.src[p.pos:], "'''") {
+			return nil, p.errorf("multiline strings are not supported")
+		}
+		return p.str()
+	case c == '[':
+		p.pos++
+		values := make([]interface{}, 0)
+		for {
+			p.skip(true)
+			if p.eof() {
+				return nil, p.errorf("unterminated array")
+			}
+			if p.peek() == ']' {
+				p.pos++
+				return values, nil
+			}
+			value, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+			p.skip(true)
+			if !p.eof() && p.peek() == ',' {
+				p.pos++
+			} else if p.eof() || p.peek() != ']' {
+				return nil, p.errorf("expected ',' or ']' in array")
+			}
+		}
+	case c == '{':
+		return nil, p.errorf("inline tables are not supported")
+	}
+
+	start := p.pos
+	for !p.eof() && strings.IndexByte(" \t\r\n#,]", p.peek()) < 0 {
+		p.pos++
+	}
+	token := p.src[start:p.pos]
+	switch token {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "inf", "+inf", "-inf", "nan", "+nan", "-nan":
+		f, _ := strconv.ParseFloat(strings.Replace(token, "nan", "NaN", 1), 64)
+		return f, nil
+	}
+	if i, err := strconv.ParseInt(strings.Replace(token, "_", "", -1), 0, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(strings.Replace(token, "_", "", -1), 64); err == nil {
+		return f, nil
+	}
+	return nil, p.errorf("unsupported value %q", token)
+}
+
+// str parses a basic (double quoted) or literal (single quoted) string.
+func (p *tomlParser) str() (string, error) {
+	quote := p.peek()
+	p.pos++
+	var b strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.peek()
+		p.pos++
+		if c == quote {
+			return b.String(), nil
+		}
+		if c != '\\' || quote == '\'' {
+			b.WriteByte(c)
+			continue
+		}
+		if p.eof() {
+			return "", p.errorf("unterminated string")
+		}
+		escape := p.peek()
+		p.pos++
+		switch escape {
+		case 'b':
+			b.WriteByte('\b')
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'f':
+			b.WriteByte('\f')
+		case 'r':
+			b.WriteByte('\r')
+		case '"', '\\':
+			b.WriteByte(escape)
+		case 'u', 'U':
+			n := 4
+			if escape == 'U' {
+				n = 8
+			}
+			if p.pos+n > len(p.src) {
+				return "", p.errorf("malformed unicode escape")
+			}
+			r, err := strconv.ParseUint(p.src[p.pos:p.pos+n], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(r)) {
+				return "", p.errorf("malformed unicode escape")
+			}
+			b.WriteRune(rune(r))
+			p.pos += n
+		default:
+			return "", p.errorf("unsupported escape sequence \\%c", escape)
+		}
+	}
+}
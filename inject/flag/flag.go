@@ -7,23 +7,80 @@ import (
 	stdFlag "flag"
 	"reflect"
 
+	"github.com/go-modules/modules/inject"
 	"github.com/go-modules/modules/inject/literal"
 )
 
 // Injector is an inject.Injector for parsing command line flags.
 var Injector = &injector{stdFlag.CommandLine}
 
+// New returns an inject.Injector for parsing the flags of fs, which should be parsed prior to injection.
+func New(fs *stdFlag.FlagSet) inject.Injector {
+	return &injector{fs}
+}
+
 // An injector wraps a FlagSet and implements inject.Injector
 type injector struct {
 	*stdFlag.FlagSet
 }
 
 // Inject looks up the flag by name and sets the value via literal.Injector.
-// Only sets value if the flag is set, otherwise passes by returning (false, nil).
+// Only sets value if the flag was explicitly set on the command line, otherwise passes by returning (false, nil).
+// Flags set to the empty string are injected, while default values are not.
 func (v injector) Inject(value reflect.Value, name string) (bool, error) {
 	f := v.Lookup(name)
-	if f == nil || f.Value.String() == "" {
+	if f == nil || !v.isSet(name) {
 		return false, nil
 	}
 	return literal.Injector.Inject(value, f.Value.String())
 }
+
+// isSet returns true if the flag named name was set when parsing.
+func (v injector) isSet(name string) bool {
+	set := false
+	v.Visit(func(f *stdFlag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// Define defines a flag on fs for values of type typ, unless a flag named name is already defined.
+// Flag values are validated by parsing them via literal.Injector. The default value defValue is only displayed in
+// usage messages, since unset flags are not injected and other tag keys may set the value instead.
+func Define(fs *stdFlag.FlagSet, name string, typ reflect.Type, defValue, usage string) {
+	if fs.Lookup(name) != nil {
+		return
+	}
+	fs.Var(&value{typ: typ}, name, usage)
+	fs.Lookup(name).DefValue = defValue
+}
+
+// A value is a stdlib flag.Value for a given type.
+type value struct {
+	typ reflect.Type
+	str string
+}
+
+// String returns the flag value as set, or the empty string if unset.
+func (v *value) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.str
+}
+
+// Set validates str by parsing it into a value of v's type.
+func (v *value) Set(str string) error {
+	if _, err := literal.Injector.Inject(reflect.New(v.typ).Elem(), str); err != nil {
+		return err
+	}
+	v.str = str
+	return nil
+}
+
+// IsBoolFlag allows bool flags to be set without a value, e.g. -verbose.
+func (v *value) IsBoolFlag() bool {
+	return v.typ.Kind() == reflect.Bool
+}
@@ -41,3 +41,64 @@ func TestInjector(t *testing.T) {
 		}
 	}
 }
+
+func TestDefine(t *testing.T) {
+	fs := flag.NewFlagSet("test set", flag.ContinueOnError)
+	Define(fs, "verbose", reflect.TypeOf(false), "false", "")
+	Define(fs, "port", reflect.TypeOf(0), "80", "")
+	if err := fs.Parse([]string{"-verbose"}); err != nil {
+		t.Fatalf("failed to parse flags: %s", err)
+	}
+
+	var verbose bool
+	if ok, err := New(fs).Inject(reflect.ValueOf(&verbose).Elem(), "verbose"); err != nil {
+		t.Error(err)
+	} else if !ok || !verbose {
+		t.Error("expected verbose to be set")
+	}
+
+	var port int
+	if ok, err := New(fs).Inject(reflect.ValueOf(&port).Elem(), "port"); err != nil {
+		t.Error(err)
+	} else if ok {
+		t.Error("expected unset port not to be injected")
+	}
+	if def := fs.Lookup("port").DefValue; def != "80" {
+		t.Errorf("expected default %q but got %q", "80", def)
+	}
+}
+
+func TestInjectorSetDetection(t *testing.T) {
+	fs := flag.NewFlagSet("test set", flag.ContinueOnError)
+	fs.Bool("verbose", false, "")
+	fs.Bool("debug", true, "")
+	fs.String("name", "default", "")
+	if err := fs.Parse([]string{"-name", "", "-debug=false"}); err != nil {
+		t.Fatalf("failed to parse flags: %s", err)
+	}
+	injector := New(fs)
+
+	// Defaults are not injected.
+	verbose := true
+	if ok, err := injector.Inject(reflect.ValueOf(&verbose).Elem(), "verbose"); err != nil {
+		t.Error(err)
+	} else if ok {
+		t.Error("expected unset bool flag not to be injected")
+	} else if !verbose {
+		t.Error("expected value to be unchanged")
+	}
+
+	// Explicitly set values are injected, even when empty or false.
+	debug := true
+	if ok, err := injector.Inject(reflect.ValueOf(&debug).Elem(), "debug"); err != nil {
+		t.Error(err)
+	} else if !ok || debug {
+		t.Error("expected debug to be set to false")
+	}
+	name := "value"
+	if ok, err := injector.Inject(reflect.ValueOf(&name).Elem(), "name"); err != nil {
+		t.Error(err)
+	} else if !ok || name != "" {
+		t.Errorf("expected name to be set to the empty string, got %q", name)
+	}
+}
@@ -0,0 +1,275 @@
+// Package gnuflag provides an inject.Injector to set values from command line flags following GNU conventions.
+//
+// Supported syntax includes:
+//   - Long flags with values: --db-host=localhost, --db-host localhost
+//   - Short flags with values: -d localhost, -dlocalhost
+//   - Bool flags: --verbose, --verbose=false, --no-verbose, -v, and combined short flags: -vq
+//   - Repeated flags, which accumulate into slice values: --tag a --tag b
+//   - Termination of flag parsing by --, after which all arguments are positional
+//
+// Tag values are the long flag name, followed by an optional short name option, e.g. gnuflag:"db-host,short=d".
+// Flags must be defined prior to parsing, which the modules.GNUFlags binder option does for tagged fields on each Bind.
+// String flag values are passed through literal.Injector.
+package gnuflag
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/go-modules/modules/inject/literal"
+	"github.com/go-modules/modules/tags"
+)
+
+// A FlagSet holds flag definitions and the results of parsing arguments. It implements inject.Injector.
+type FlagSet struct {
+	sync.RWMutex
+	args   []string
+	long   map[string]*flagSpec
+	short  map[rune]*flagSpec
+	values map[string][]string
+	rest   []string
+	parsed bool
+}
+
+// A flagSpec defines a flag.
+type flagSpec struct {
+	name  string
+	short rune
+	typ   reflect.Type
+}
+
+// isBool returns true if the flag does not require a value.
+func (f *flagSpec) isBool() bool {
+	return f.typ.Kind() == reflect.Bool
+}
+
+// NewFlagSet returns a new FlagSet for parsing args, or os.Args[1:] if args is nil.
+func NewFlagSet(args []string) *FlagSet {
+	if args == nil {
+		args = os.Args[1:]
+	}
+	return &FlagSet{
+		args:   args,
+		long:   make(map[string]*flagSpec),
+		short:  make(map[rune]*flagSpec),
+		values: make(map[string][]string),
+	}
+}
+
+// ParseTag splits a tag value into a long flag name and an optional short flag name.
+func ParseTag(tagValue string) (string, rune, error) {
+	name, options := tags.ParseTag(tagValue)
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, "= ") {
+		return "", 0, fmt.Errorf("invalid flag name: %q", name)
+	}
+	var short rune
+	for _, option := range strings.Split(string(options), ",") {
+		if strings.HasPrefix(option, "short=") {
+			s := option[len("short="):]
+			if utf8.RuneCountInString(s) != 1 || s == "-" {
+				return "", 0, fmt.Errorf("invalid short flag name for %s: %q", name, s)
+			}
+			short, _ = utf8.DecodeRuneInString(s)
+		}
+	}
+	return name, short, nil
+}
+
+// Define defines a flag from tagValue for values of type typ. Defining the same flag again with the same type has no
+// effect. Flags defined after parsing are set when Parse is called again.
+func (fs *FlagSet) Define(tagValue string, typ reflect.Type) error {
+	name, short, err := ParseTag(tagValue)
+	if err != nil {
+		return err
+	}
+	fs.Lock()
+	defer fs.Unlock()
+	if existing, ok := fs.long[name]; ok {
+		if existing.typ != typ || short != 0 && existing.short != short {
+			return fmt.Errorf("conflicting definitions of flag --%s", name)
+		}
+		return nil
+	}
+	spec := &flagSpec{name: name, short: short, typ: typ}
+	if short != 0 {
+		if existing, ok := fs.short[short]; ok {
+			return fmt.Errorf("short flag -%c defined for both --%s and --%s", short, existing.name, name)
+		}
+		fs.short[short] = spec
+	}
+	fs.long[name] = spec
+	return nil
+}
+
+// Parse parses the arguments according to the defined flags. All problems are reported together as Errors.
+// Parsing again replaces the results of previous calls, e.g. to set flags defined since.
+func (fs *FlagSet) Parse() error {
+	fs.Lock()
+	defer fs.Unlock()
+	fs.parsed = true
+	fs.values = make(map[string][]string)
+	fs.rest = nil
+
+	var errs Errors
+	args := fs.args
+	for len(args) > 0 {
+		arg := args[0]
+		args = args[1:]
+		switch {
+		case arg == "--":
+			fs.rest = append(fs.rest, args...)
+			args = nil
+		case strings.HasPrefix(arg, "--"):
+			var err error
+			if args, err = fs.parseLong(arg[2:], args); err != nil {
+				errs = append(errs, err)
+			}
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			var err error
+			if args, err = fs.parseShort(arg[1:], args); err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			fs.rest = append(fs.rest, arg)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// parseLong parses a long flag (without the leading dashes), consuming a value from args if necessary.
+func (fs *FlagSet) parseLong(arg string, args []string) ([]string, error) {
+	name, value, hasValue := arg, "", false
+	if i := strings.Index(arg, "="); i >= 0 {
+		name, value, hasValue = arg[:i], arg[i+1:], true
+	}
+	spec, ok := fs.long[name]
+	if !ok {
+		if negated, ok := fs.long[strings.TrimPrefix(name, "no-")]; ok && strings.HasPrefix(name, "no-") && negated.isBool() && !hasValue {
+			fs.values[negated.name] = append(fs.values[negated.name], "false")
+			return args, nil
+		}
+		return args, &UnknownFlagError{"--" + name}
+	}
+	if !hasValue {
+		if spec.isBool() {
+			value = "true"
+		} else if len(args) == 0 {
+			return args, fmt.Errorf("flag needs a value: --%s", name)
+		} else {
+			value, args = args[0], args[1:]
+		}
+	}
+	return args, fs.set(spec, "--"+name, value)
+}
+
+// parseShort parses one or more combined short flags (without the leading dash), consuming a value from args if
+// necessary.
+func (fs *FlagSet) parseShort(arg string, args []string) ([]string, error) {
+	for arg != "" {
+		r, size := utf8.DecodeRuneInString(arg)
+		arg = arg[size:]
+		spec, ok := fs.short[r]
+		if !ok {
+			return args, &UnknownFlagError{"-" + string(r)}
+		}
+		if spec.isBool() {
+			if err := fs.set(spec, "-"+string(r), "true"); err != nil {
+				return args, err
+			}
+			continue
+		}
+		// The remainder of the argument, or else the next argument, is the value.
+		value := arg
+		if value == "" {
+			if len(args) == 0 {
+				return args, fmt.Errorf("flag needs a value: -%c", r)
+			}
+			value, args = args[0], args[1:]
+		}
+		return args, fs.set(spec, "-"+string(r), value)
+	}
+	return args, nil
+}
+
+// set validates value and records it for spec.
+func (fs *FlagSet) set(spec *flagSpec, flag, value string) error {
+	typ := spec.typ
+	if isAccumulating(typ) {
+		typ = typ.Elem()
+	}
+	if _, err := literal.Injector.Inject(reflect.New(typ).Elem(), value); err != nil {
+		return fmt.Errorf("invalid value %q for flag %s: %s", value, flag, err)
+	}
+	fs.values[spec.name] = append(fs.values[spec.name], value)
+	return nil
+}
+
+// isAccumulating returns true if repeated flags of type typ accumulate values.
+func isAccumulating(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Slice && typ.Elem().Kind() != reflect.Uint8
+}
+
+// Args returns the positional arguments remaining after parsing.
+func (fs *FlagSet) Args() []string {
+	fs.RLock()
+	defer fs.RUnlock()
+	return fs.rest
+}
+
+// Inject sets value from the flag named by tagValue via literal.Injector. Slice values are set from all occurrences
+// of the flag, and other values from the last occurrence.
+// Only sets value if the flag was set, otherwise passes by returning (false, nil).
+func (fs *FlagSet) Inject(value reflect.Value, tagValue string) (bool, error) {
+	name, _, err := ParseTag(tagValue)
+	if err != nil {
+		return false, err
+	}
+	fs.RLock()
+	defer fs.RUnlock()
+	if !fs.parsed {
+		return false, errors.New("flags not parsed")
+	}
+	values, ok := fs.values[name]
+	if !ok {
+		return false, nil
+	}
+	if !isAccumulating(value.Type()) {
+		return literal.Injector.Inject(value, values[len(values)-1])
+	}
+	slice := reflect.MakeSlice(value.Type(), len(values), len(values))
+	for i, v := range values {
+		if _, err := literal.Injector.Inject(slice.Index(i), v); err != nil {
+			return false, err
+		}
+	}
+	value.Set(slice)
+	return true, nil
+}
+
+// An UnknownFlagError indicates an argument naming a flag which was not defined.
+type UnknownFlagError struct {
+	Flag string
+}
+
+func (e *UnknownFlagError) Error() string {
+	return "unknown flag: " + e.Flag
+}
+
+// Errors holds one or more errors which occurred while parsing.
+type Errors []error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
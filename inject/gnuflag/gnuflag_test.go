@@ -0,0 +1,157 @@
+package gnuflag
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testFlags struct {
+	Host    string
+	Port    int
+	Verbose bool
+	Quiet   bool
+	Cache   bool
+	Tags    []string
+}
+
+func newTestFlagSet(t *testing.T, args []string) *FlagSet {
+	fs := NewFlagSet(args)
+	for tagValue, typ := range map[string]reflect.Type{
+		"db-host,short=d": reflect.TypeOf(""),
+		"port,short=p":    reflect.TypeOf(0),
+		"verbose,short=v": reflect.TypeOf(false),
+		"quiet,short=q":   reflect.TypeOf(false),
+		"cache":           reflect.TypeOf(false),
+		"tag,short=t":     reflect.TypeOf([]string{}),
+	} {
+		if err := fs.Define(tagValue, typ); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return fs
+}
+
+func injectAll(t *testing.T, fs *FlagSet) testFlags {
+	var flags testFlags
+	for tagValue, value := range map[string]interface{}{
+		"db-host": &flags.Host,
+		"port":    &flags.Port,
+		"verbose": &flags.Verbose,
+		"quiet":   &flags.Quiet,
+		"cache":   &flags.Cache,
+		"tag":     &flags.Tags,
+	} {
+		if _, err := fs.Inject(reflect.ValueOf(value).Elem(), tagValue); err != nil {
+			t.Error(err)
+		}
+	}
+	return flags
+}
+
+func TestParse(t *testing.T) {
+	for _, testCase := range []struct {
+		args     []string
+		expected testFlags
+		rest     []string
+	}{
+		{
+			[]string{"--db-host=localhost", "--port", "8080", "--verbose"},
+			testFlags{Host: "localhost", Port: 8080, Verbose: true},
+			nil,
+		},
+		{
+			[]string{"-d", "localhost", "-p8080", "-vq", "arg"},
+			testFlags{Host: "localhost", Port: 8080, Verbose: true, Quiet: true},
+			[]string{"arg"},
+		},
+		{
+			[]string{"-vd", "localhost", "--cache", "--no-cache", "--verbose=false"},
+			testFlags{Host: "localhost"},
+			nil,
+		},
+		{
+			[]string{"--tag", "a", "first", "-t", "b", "--tag=c", "--", "--port", "-v"},
+			testFlags{Tags: []string{"a", "b", "c"}},
+			[]string{"first", "--port", "-v"},
+		},
+		{
+			[]string{"-", "--port=1", "--port=2"},
+			testFlags{Port: 2},
+			[]string{"-"},
+		},
+	} {
+		fs := newTestFlagSet(t, testCase.args)
+		if err := fs.Parse(); err != nil {
+			t.Errorf("%v: %s", testCase.args, err)
+			continue
+		}
+		if got := injectAll(t, fs); !reflect.DeepEqual(testCase.expected, got) {
+			t.Errorf("%v: expected %+v but got %+v", testCase.args, testCase.expected, got)
+		}
+		if !reflect.DeepEqual(testCase.rest, fs.Args()) {
+			t.Errorf("%v: expected args %q but got %q", testCase.args, testCase.rest, fs.Args())
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	fs := newTestFlagSet(t, []string{"--unknown", "-x", "--port", "eighty", "--db-host"})
+	err := fs.Parse()
+	errs, ok := err.(Errors)
+	if !ok {
+		t.Fatalf("expected Errors but got %v", err)
+	}
+	if len(errs) != 4 {
+		t.Fatalf("expected 4 errors but got %d: %s", len(errs), errs)
+	}
+	for i, flag := range []string{"--unknown", "-x"} {
+		if unknown, ok := errs[i].(*UnknownFlagError); !ok || unknown.Flag != flag {
+			t.Errorf("expected unknown flag %s but got %v", flag, errs[i])
+		}
+	}
+}
+
+func TestDefineConflicts(t *testing.T) {
+	fs := NewFlagSet([]string{})
+	if err := fs.Define("port,short=p", reflect.TypeOf(0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Define("port", reflect.TypeOf(0)); err != nil {
+		t.Errorf("expected redefinition to succeed: %s", err)
+	}
+	if err := fs.Define("port", reflect.TypeOf("")); err == nil {
+		t.Error("expected error redefining flag with a different type")
+	}
+	if err := fs.Define("print,short=p", reflect.TypeOf(false)); err == nil {
+		t.Error("expected error reusing short flag")
+	}
+	if err := fs.Define("-bad", reflect.TypeOf(false)); err == nil {
+		t.Error("expected error for invalid flag name")
+	}
+}
+
+func TestReparse(t *testing.T) {
+	fs := NewFlagSet([]string{"--port", "80", "--tag", "a", "rest"})
+	if err := fs.Define("port", reflect.TypeOf(0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse(); err == nil {
+		t.Fatal("expected error for unknown flag --tag")
+	}
+	if err := fs.Define("tag", reflect.TypeOf([]string(nil))); err != nil {
+		t.Fatal(err)
+	}
+	// Parsing again, with the flag defined since, replaces the previous results.
+	if err := fs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	var tags []string
+	if ok, err := fs.Inject(reflect.ValueOf(&tags).Elem(), "tag"); err != nil || !ok {
+		t.Fatalf("expected tag to be set: %v", err)
+	} else if !reflect.DeepEqual([]string{"a"}, tags) {
+		t.Errorf("expected [a] but got %q", tags)
+	}
+	if !reflect.DeepEqual([]string{"rest"}, fs.Args()) {
+		t.Errorf("expected args [rest] but got %q", fs.Args())
+	}
+}
@@ -18,3 +18,11 @@ type InjectorFunc func(reflect.Value, string) (bool, error)
 func (f InjectorFunc) Inject(value reflect.Value, tagValue string) (bool, error) {
 	return f(value, tagValue)
 }
+
+// A SecretInjector is an Injector of secret values, such as passwords read from secret files.
+// Binders redact values set by a SecretInjector from logs, hooks and effective configuration dumps.
+type SecretInjector interface {
+	Injector
+	// Secret is a marker method.
+	Secret()
+}
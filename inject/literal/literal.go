@@ -5,21 +5,93 @@
 // Interface, Struct (if string is assignable/convertible);
 // Ptr, Uintptr, UnsafePointer
 //
+// Parsers registered for a specific reflect.Type via Register take precedence over Kind based parsing, as do
+// addressable secret values (see the secret package), which are parsed by their UnmarshalText method.
+//
 // Maps are not supported
 package literal
 
 import (
+	"encoding"
 	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/go-modules/modules/inject"
 )
 
 // Injector is an inject.Injector for parsing string literals.
-var Injector = inject.TypedInjector(&valueMaker{})
+var Injector = inject.InjectorFunc(Inject)
+
+// typedInjector parses string literals based on Kind.
+var typedInjector = inject.TypedInjector(&valueMaker{})
+
+// Inject sets value by parsing str with the Parser registered for value's type, if any, or else via the
+// UnmarshalText method of secret values, or else based on value's Kind.
+func Inject(value reflect.Value, str string) (bool, error) {
+	if parser, ok := lookup(value.Type()); ok {
+		parsed, err := parser(str)
+		if err != nil {
+			return false, err
+		}
+		if !parsed.IsValid() {
+			return false, fmt.Errorf("parser for type %s returned an invalid value", value.Type())
+		}
+		if parsed.Type().AssignableTo(value.Type()) {
+			value.Set(parsed)
+		} else if parsed.Type().ConvertibleTo(value.Type()) {
+			value.Set(parsed.Convert(value.Type()))
+		} else {
+			return false, fmt.Errorf("parser for type %s returned value of type %s", value.Type(), parsed.Type())
+		}
+		return true, nil
+	}
+	if value.CanAddr() {
+		if unmarshaler, ok := value.Addr().Interface().(secretUnmarshaler); ok {
+			if err := unmarshaler.UnmarshalText([]byte(str)); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+	}
+	return typedInjector.Inject(value, str)
+}
+
+// A secretUnmarshaler is a secret value which parses itself, e.g. a *secret.Secret[T].
+// Other encoding.TextUnmarshalers are parsed based on Kind, like any other type.
+type secretUnmarshaler interface {
+	encoding.TextUnmarshaler
+	// Secret is the marker method of secret.Value.
+	Secret()
+}
+
+// A Parser parses a string into a value of the type it was registered for.
+type Parser func(string) (reflect.Value, error)
+
+// parsers holds registered Parsers by type.
+var parsers = struct {
+	sync.RWMutex
+	m map[reflect.Type]Parser
+}{m: make(map[reflect.Type]Parser)}
+
+// Register registers parser for values of type typ, replacing any previously registered Parser.
+// Registered Parsers are used by Injector, and therefore by the env, flag and file injectors as well.
+func Register(typ reflect.Type, parser Parser) {
+	parsers.Lock()
+	parsers.m[typ] = parser
+	parsers.Unlock()
+}
+
+// lookup returns the Parser registered for typ, if any.
+func lookup(typ reflect.Type) (Parser, bool) {
+	parsers.RLock()
+	parser, ok := parsers.m[typ]
+	parsers.RUnlock()
+	return parser, ok
+}
 
 // valueMaker implements a subset of tags.*Maker interfaces.
 type valueMaker struct{}
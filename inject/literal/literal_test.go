@@ -1,6 +1,9 @@
 package literal
 
 import (
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -29,9 +32,9 @@ func TestMakeBool(t *testing.T) {
 		if ok, got, err := fixture.MakeBool(testCase.literal); err != nil {
 			t.Errorf("unexpected error: %s", err)
 		} else if !ok {
-			t.Errorf("expected bool %q to be made", testCase.expected)
+			t.Errorf("expected bool %t to be made", testCase.expected)
 		} else if got != testCase.expected {
-			t.Errorf("expected %q got %q", testCase.expected, got)
+			t.Errorf("expected %t got %t", testCase.expected, got)
 		}
 	}
 }
@@ -138,3 +141,73 @@ func TestMakeComplex(t *testing.T) {
 		}
 	}
 }
+
+type logLevel int
+
+func TestRegister(t *testing.T) {
+	Register(reflect.TypeOf(logLevel(0)), func(str string) (reflect.Value, error) {
+		switch strings.ToLower(str) {
+		case "debug":
+			return reflect.ValueOf(logLevel(0)), nil
+		case "info":
+			return reflect.ValueOf(logLevel(1)), nil
+		case "warn":
+			return reflect.ValueOf(1), nil
+		case "none":
+			return reflect.Value{}, nil
+		}
+		return reflect.Value{}, errors.New("unknown level: " + str)
+	})
+
+	for _, testCase := range []struct {
+		literal  string
+		expected logLevel
+	}{
+		{"debug", 0},
+		{"INFO", 1},
+		// Convertible values are converted.
+		{"warn", 1},
+	} {
+		var level logLevel
+		if ok, err := Injector.Inject(reflect.ValueOf(&level).Elem(), testCase.literal); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		} else if !ok {
+			t.Errorf("expected level %q to be set", testCase.literal)
+		} else if level != testCase.expected {
+			t.Errorf("expected %d got %d", testCase.expected, level)
+		}
+	}
+
+	var level logLevel
+	if _, err := Injector.Inject(reflect.ValueOf(&level).Elem(), "unknown"); err == nil {
+		t.Error("expected error from registered parser")
+	}
+	if _, err := Injector.Inject(reflect.ValueOf(&level).Elem(), "none"); err == nil {
+		t.Error("expected error for invalid value from registered parser")
+	}
+
+	// Other types with the same Kind are unaffected.
+	var i int
+	if _, err := Injector.Inject(reflect.ValueOf(&i).Elem(), "5"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	} else if i != 5 {
+		t.Errorf("expected 5 got %d", i)
+	}
+}
+
+type upperText string
+
+func (u *upperText) UnmarshalText(text []byte) error {
+	*u = upperText(strings.ToUpper(string(text)))
+	return nil
+}
+
+func TestTextUnmarshaler(t *testing.T) {
+	// Only secret values are parsed via UnmarshalText, so other types keep Kind based parsing.
+	var u upperText
+	if _, err := Injector.Inject(reflect.ValueOf(&u).Elem(), "text"); err != nil {
+		t.Fatal(err)
+	} else if u != "text" {
+		t.Errorf("expected %q got %q", "text", u)
+	}
+}
@@ -0,0 +1,48 @@
+// Package secretfile provides an inject.Injector to set values from secret files, such as those mounted by Docker and
+// Kubernetes in /run/secrets. The tag value is the secret name, i.e. the file name within the secrets directory.
+// Trailing newlines are trimmed, and file contents are parsed by literal.Injector.
+package secretfile
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/go-modules/modules/inject"
+	"github.com/go-modules/modules/inject/literal"
+)
+
+// DefaultDir is the default secrets directory.
+const DefaultDir = "/run/secrets"
+
+// Injector is an inject.SecretInjector for secret files in DefaultDir.
+var Injector = New(DefaultDir)
+
+// New returns an inject.SecretInjector for secret files in dir.
+func New(dir string) inject.SecretInjector {
+	return injector(dir)
+}
+
+// An injector reads secret files from a directory and implements inject.SecretInjector.
+type injector string
+
+// Inject reads the secret file with name, and sets the value via literal.Injector.
+// Only sets value if the file exists, otherwise passes by returning (false, nil).
+func (i injector) Inject(value reflect.Value, name string) (bool, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return false, errors.New("invalid secret name: " + name)
+	}
+	bytes, err := ioutil.ReadFile(filepath.Join(string(i), name))
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return literal.Injector.Inject(value, strings.TrimRight(string(bytes), "\r\n"))
+}
+
+// Secret marks values set by i as secret.
+func (injector) Secret() {}
@@ -0,0 +1,42 @@
+package secretfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestInjector(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "db_password"), []byte("hunter2\n\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	injector := New(dir)
+
+	var s string
+	if ok, err := injector.Inject(reflect.ValueOf(&s).Elem(), "db_password"); err != nil {
+		t.Error(err)
+	} else if !ok {
+		t.Error("expected value to be set")
+	} else if s != "hunter2" {
+		t.Errorf("expected %q but got %q", "hunter2", s)
+	}
+
+	if ok, err := injector.Inject(reflect.ValueOf(&s).Elem(), "missing"); err != nil {
+		t.Error(err)
+	} else if ok {
+		t.Error("expected missing secret not to be set")
+	}
+
+	for _, name := range []string{"../db_password", "", ".."} {
+		if _, err := injector.Inject(reflect.ValueOf(&s).Elem(), name); err == nil {
+			t.Errorf("%q: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Package stdin provides an inject.Injector for standard input.
+// The tag value is the input type, which is decoded like files of the same type by file.Decode.
+// Supported types include: txt, json, xml, gob
+// Values of type []byte are set to the raw input, like file.Inject does for file contents.
+//
+// Input is read once, so that multiple values may be set from it.
+package stdin
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/go-modules/modules/inject"
+	"github.com/go-modules/modules/inject/file"
+)
+
+// Injector is an inject.Injector for os.Stdin.
+var Injector = New(os.Stdin)
+
+// New returns an inject.Injector which reads input from r.
+func New(r io.Reader) inject.Injector {
+	return &injector{r: r}
+}
+
+// An injector reads input once and implements inject.Injector.
+type injector struct {
+	r     io.Reader
+	once  sync.Once
+	input []byte
+	err   error
+}
+
+// Inject decodes the input into value according to the type in tagValue.
+func (i *injector) Inject(value reflect.Value, inputType string) (bool, error) {
+	i.once.Do(func() {
+		i.input, i.err = ioutil.ReadAll(i.r)
+	})
+	if i.err != nil {
+		return false, i.err
+	}
+	if value.Type() == typeOfBytes {
+		value.Set(reflect.ValueOf(append([]byte(nil), i.input...)))
+		return true, nil
+	}
+	if err := file.Decode(bytes.NewReader(i.input), inputType, value); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+var typeOfBytes = reflect.TypeOf([]byte(nil))
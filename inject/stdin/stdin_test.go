@@ -0,0 +1,48 @@
+package stdin
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type jsonType struct {
+	Test string `json:"test"`
+}
+
+func TestInjector(t *testing.T) {
+	injector := New(strings.NewReader(`{"test": "value"}`))
+
+	var j jsonType
+	if ok, err := injector.Inject(reflect.ValueOf(&j).Elem(), "json"); err != nil {
+		t.Error(err)
+	} else if !ok {
+		t.Error("expected value to be set")
+	} else if j.Test != "value" {
+		t.Errorf("expected %q but got %q", "value", j.Test)
+	}
+
+	// Input may be read again.
+	var s string
+	if ok, err := injector.Inject(reflect.ValueOf(&s).Elem(), "txt"); err != nil {
+		t.Error(err)
+	} else if !ok {
+		t.Error("expected value to be set")
+	} else if s != `{"test": "value"}` {
+		t.Errorf("expected input but got %q", s)
+	}
+
+	// Byte slices are set to the raw input.
+	var b []byte
+	if ok, err := injector.Inject(reflect.ValueOf(&b).Elem(), "txt"); err != nil {
+		t.Error(err)
+	} else if !ok {
+		t.Error("expected value to be set")
+	} else if string(b) != `{"test": "value"}` {
+		t.Errorf("expected input but got %q", b)
+	}
+
+	if _, err := injector.Inject(reflect.ValueOf(&s).Elem(), "unknown"); err == nil {
+		t.Error("expected error for unknown input type")
+	}
+}
@@ -0,0 +1,88 @@
+package modules
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// interpolate replaces ${name} and ${name:-default} references in a tag value, and unescapes $$ to $.
+// Names are resolved from values provided earlier in the binding (preferring strings, when several types are provided
+// with the same name), and then from the environment. A reference which cannot be resolved is an error, unless it
+// has a default. References to secret values, or ambiguous references to several non-string types, are errors.
+func (b *binding) interpolate(tagValue string) (string, error) {
+	if !strings.Contains(tagValue, "$") {
+		return tagValue, nil
+	}
+	var buf strings.Builder
+	for i := 0; i < len(tagValue); i++ {
+		c := tagValue[i]
+		if c != '$' || i+1 >= len(tagValue) {
+			buf.WriteByte(c)
+			continue
+		}
+		switch tagValue[i+1] {
+		case '$':
+			buf.WriteByte('$')
+			i++
+		case '{':
+			end := strings.IndexByte(tagValue[i:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated reference in tag value %q", tagValue)
+			}
+			ref := tagValue[i+2 : i+end]
+			name, def, hasDefault := ref, "", false
+			if j := strings.Index(ref, ":-"); j >= 0 {
+				name, def, hasDefault = ref[:j], ref[j+2:], true
+			}
+			value, ok, err := b.resolve(name)
+			if err != nil {
+				return "", fmt.Errorf("%s in tag value %q", err, tagValue)
+			}
+			if ok && (value != "" || !hasDefault) {
+				buf.WriteString(value)
+			} else if hasDefault {
+				buf.WriteString(def)
+			} else {
+				return "", fmt.Errorf("undefined reference ${%s} in tag value %q", name, tagValue)
+			}
+			i += end
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String(), nil
+}
+
+// resolve returns the formatted value provided as name, or else the environment variable name.
+func (b *binding) resolve(name string) (string, bool, error) {
+	bound, ok := b.fields.get(bindKey{typeOfString, name})
+	if !ok {
+		matches := b.fields.getByName(name)
+		if len(matches) > 1 {
+			types := make([]string, 0, len(matches))
+			for key := range matches {
+				types = append(types, key.Type.String())
+			}
+			sort.Strings(types)
+			return "", false, fmt.Errorf("ambiguous reference ${%s} to values of types %s", name, strings.Join(types, ", "))
+		}
+		for _, match := range matches {
+			bound, ok = match, true
+		}
+	}
+	if ok {
+		if bound.secret {
+			// Interpolated values are not redacted, so would leak the secret.
+			return "", false, fmt.Errorf("reference ${%s} to a secret value is not allowed", name)
+		}
+		if bound.value.IsValid() && bound.value.CanInterface() {
+			return fmt.Sprint(bound.value.Interface()), true, nil
+		}
+	}
+	value, ok := b.lookupEnv(name)
+	return value, ok, nil
+}
+
+var typeOfString = reflect.TypeOf("")
@@ -3,17 +3,23 @@ package modules
 
 import (
 	"errors"
+	stdFlag "flag"
 	"fmt"
 	"io"
 	"log"
+	"os"
 	"reflect"
 	"sync"
 
 	"github.com/go-modules/modules/inject"
+	"github.com/go-modules/modules/inject/arg"
 	"github.com/go-modules/modules/inject/env"
 	"github.com/go-modules/modules/inject/file"
 	"github.com/go-modules/modules/inject/flag"
 	"github.com/go-modules/modules/inject/literal"
+	"github.com/go-modules/modules/inject/secretfile"
+	"github.com/go-modules/modules/inject/stdin"
+	"github.com/go-modules/modules/secret"
 	"github.com/go-modules/modules/tags"
 )
 
@@ -32,16 +38,41 @@ type Binder struct {
 	logger *log.Logger
 	// Injectors by tag key.
 	injectors map[string]inject.Injector
+	// The .env files to load when binding, if configured.
+	dotEnv *DotEnv
+	// Tag keys disabled by DisableInjectors, which are not registered when binding.
+	disabled map[string]bool
+	// Called for each provided value.
+	hooks []ProvideHook
+	// The command line flags to define and parse when binding, if configured.
+	flags *Flags
+	// The GNU style command line flags to define and parse when binding, if configured.
+	gnuFlags *GNUFlags
+	// Resources opened by successful bindings, to close when the Binder is closed.
+	closers closers
+	// Values provided with the 'reload' option by successful bindings.
+	reloads reloads
+	// Reloads values automatically, if configured.
+	autoReload *AutoReload
+	// Starts automatic reloading once.
+	startReload sync.Once
+	// Closed to stop automatic reloading.
+	stopReload chan struct{}
 }
 
 // NewBinder initializes a new Binder instance, and applies options.
 func NewBinder(options ...BinderOption) *Binder {
 	b := &Binder{
+		stopReload: make(chan struct{}),
+		disabled:   make(map[string]bool),
 		injectors: map[string]inject.Injector{
-			"literal": literal.Injector,
-			"env":     env.Injector,
-			"flag":    flag.Injector,
-			"file":    file.Injector,
+			"literal":    literal.Injector,
+			"env":        env.Injector,
+			"flag":       flag.Injector,
+			"file":       file.Injector,
+			"arg":        arg.Injector,
+			"stdin":      stdin.Injector,
+			"secretfile": secretfile.Injector,
 		},
 	}
 
@@ -71,11 +102,90 @@ type Injectors map[string]inject.Injector
 func (v Injectors) configure(b *Binder) {
 	for k, v := range v {
 		b.injectors[k] = v
+		delete(b.disabled, k)
 	}
 }
 
+// DisableInjectors is a functional option that removes Injectors from a Binder by tag key, e.g. to disable built-in
+// Injectors such as 'file' and 'flag' when binding in a library or embedded application.
+// Options are applied in order, so Injectors may be re-added by later options.
+type DisableInjectors []string
+
+func (d DisableInjectors) configure(b *Binder) {
+	for _, k := range d {
+		delete(b.injectors, k)
+		b.disabled[k] = true
+	}
+}
+
+// DotEnv is a functional option which loads .env files when binding. Variables are injected via the 'dotenv' tag key,
+// and the 'env' tag key's injector (if created by env.New) looks up variables from both the files and its configured
+// source, keeping its other options. Disabled tag keys are not registered.
+type DotEnv struct {
+	// Paths of the .env files to load, defaulting to ".env". Files later in Paths override earlier ones.
+	// Files which do not exist are skipped.
+	Paths []string
+	// Override gives variables from .env files precedence over the process environment.
+	Override bool
+}
+
+func (d DotEnv) configure(b *Binder) {
+	b.dotEnv = &d
+}
+
+// Flags is a functional option which defines command line flags for the 'flag' tagged provided fields of bound
+// modules, and parses arguments before binding. Flag types are taken from the fields, default values from 'literal'
+// tags, and usage messages from 'usage' tags. The 'flag' tag key is replaced with an injector for FlagSet, and the
+// 'arg' tag key with an injector for its remaining positional arguments.
+type Flags struct {
+	// The FlagSet to define flags on, defaulting to flag.CommandLine.
+	FlagSet *stdFlag.FlagSet
+	// The arguments to parse, defaulting to os.Args[1:].
+	Args []string
+}
+
+func (f Flags) configure(b *Binder) {
+	if f.FlagSet == nil {
+		f.FlagSet = stdFlag.CommandLine
+	}
+	if f.Args == nil {
+		f.Args = os.Args[1:]
+	}
+	b.flags = &f
+	b.injectors["flag"] = flag.New(f.FlagSet)
+	b.injectors["arg"] = arg.New(f.FlagSet.Args)
+}
+
+// parse defines a flag for each 'flag' tagged provided field of modules, and parses the arguments.
+func (f *Flags) parse(modules []interface{}) error {
+	err := providedFields(modules, func(_ reflect.Type, field reflect.StructField, tag tags.StructTag) error {
+		if name, ok := tag.Get("flag"); ok {
+			if field.PkgPath != "" {
+				return fmt.Errorf("cannot define flag %s for unexported field: %s", name, field.Name)
+			}
+			defValue, _ := tag.Get("literal")
+			provide, _ := tag.Get("provide")
+			if _, options := tags.ParseTag(provide); defValue != "" && isSecretField(field, options) {
+				// Usage messages show default values.
+				defValue = secret.Redacted
+			}
+			usage, _ := tag.Get("usage")
+			flag.Define(f.FlagSet, name, field.Type, defValue, usage)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := f.FlagSet.Parse(f.Args); err != nil {
+		return &AnnotatedError{msg: "failed to parse command line flags", cause: err}
+	}
+	return nil
+}
+
 // Bind binds modules. Calls Provide() on modules implementing Provider, calls
 // inject.Injectors for tagged fields, and injects provided fields.
+// Resources opened by injectors are closed by Close(), or when Bind returns an error.
 func (b *Binder) Bind(modules ...interface{}) error {
 	binding := newBinding(b)
 	// Holds errors during binding.
@@ -89,32 +199,92 @@ func (b *Binder) Bind(modules ...interface{}) error {
 		return errors.New("the 'inject' tag key may not be overridden")
 	}
 
-	// Collect errors in a goroutine.
+	if b.dotEnv != nil {
+		if err := binding.loadDotEnv(*b.dotEnv); err != nil {
+			return &AnnotatedError{msg: "failed to load .env files", cause: err}
+		}
+	}
+
+	if b.flags != nil {
+		if err := b.flags.parse(modules); err != nil {
+			return err
+		}
+	}
+	if b.gnuFlags != nil {
+		if err := b.gnuFlags.parse(modules); err != nil {
+			return err
+		}
+	}
+
+	// Collect errors in a goroutine, which signals done when binding.errors is closed.
+	done := make(chan struct{})
 	go func() {
 		for err := range binding.errors {
 			errs = append(errs, err)
 		}
+		close(done)
 	}()
 
 	// Injection goroutines signal here when complete.
 	var injections sync.WaitGroup
 
+	// finish cancels injections of values which were not provided, and waits for all goroutines to complete.
+	// Resources opened while binding are kept until the Binder is closed if binding succeeded, or else closed.
+	finish := func(aborted bool) {
+		close(binding.cancel)
+		injections.Wait()
+		close(binding.errors)
+		<-done
+		if !aborted && len(errs) == 0 {
+			for _, closer := range binding.opened.take() {
+				b.closers.track(closer)
+			}
+			binding.addListeners(modules)
+			b.reloads.add(binding.reloadables)
+			if b.autoReload != nil {
+				b.startReload.Do(b.startAutoReload)
+			}
+		} else {
+			for _, err := range binding.opened.close() {
+				b.logf("failed to close resource: %s\n", err)
+			}
+		}
+	}
+
 	// Bind each module.
 	for _, module := range modules {
 
-		// If this module is a Provider then call Provide().
+		moduleType := reflect.TypeOf(module).Elem()
+
+		// If this module is a Provider then call Provide(), retaining copies of the prior values of provided fields
+		// to determine which it sets.
+		before := make([]reflect.Value, moduleType.NumField())
 		if provider, ok := module.(Provider); ok {
+			for i := range before {
+				if _, ok := tags.StructTag(string(moduleType.Field(i).Tag)).Get("provide"); ok {
+					before[i] = snapshot(reflect.ValueOf(module).Elem().Field(i))
+				}
+			}
 			if err := provider.Provide(); err != nil {
+				finish(true)
 				return &AnnotatedError{msg: "error during call to Provide()", cause: err}
 			}
 		}
 
 		// Bind each field in this module.
-		moduleType := reflect.TypeOf(module).Elem()
 		for i := 0; i < moduleType.NumField(); i++ {
 			field := moduleType.Field(i)
 			value := reflect.ValueOf(module).Elem().Field(i)
 			tag := tags.StructTag(string(field.Tag))
+			// Malformed tags are errors, rather than dropping the keys following the malformation.
+			if err := tag.Check(); err != nil {
+				var syntaxErr *tags.TagSyntaxError
+				if errors.As(err, &syntaxErr) {
+					syntaxErr.Field = typeName(moduleType) + "." + field.Name
+				}
+				binding.errors <- err
+				continue
+			}
 			if bindName, ok := tag.Get("inject"); ok {
 				if !value.CanSet() {
 					binding.errors <- fmt.Errorf("cannot inject unexported field: %s", field.Name)
@@ -128,19 +298,25 @@ func (b *Binder) Bind(modules ...interface{}) error {
 				}()
 			} else if tagValue, ok := tag.Get("provide"); ok {
 				bindName, options := tags.ParseTag(tagValue)
+				ctx := inject.InjectionContext{
+					Field:      field,
+					ModuleType: moduleType,
+					Name:       bindName,
+					Options:    options,
+					Logger:     b.logger,
+					OnClose:    binding.opened.track,
+					Cache:      &binding.cache,
+				}
 				// Releases blocking injections for key.
-				if err := binding.provide(bindName, options.Contains("singleton"), tag, value); err != nil {
+				if err := binding.provide(ctx, value, presetSource(before[i], value)); err != nil {
 					binding.errors <- err
 				}
 			}
 		}
 	}
 
-	// Wait for all injection goroutines to complete.
-	injections.Wait()
-
-	// Signal error processing goroutine to complete.
-	close(binding.errors)
+	// All values have been provided, so signal waiting injection goroutines, and wait for all goroutines to complete.
+	finish(false)
 
 	if len(errs) > 0 {
 		return &BindingError{errs}
@@ -149,6 +325,24 @@ func (b *Binder) Bind(modules ...interface{}) error {
 	return nil
 }
 
+// providedFields calls fn for each field of modules tagged with 'provide', until fn returns an error.
+func providedFields(modules []interface{}, fn func(moduleType reflect.Type, field reflect.StructField, tag tags.StructTag) error) error {
+	for _, module := range modules {
+		moduleType := reflect.TypeOf(module).Elem()
+		for i := 0; i < moduleType.NumField(); i++ {
+			field := moduleType.Field(i)
+			tag := tags.StructTag(string(field.Tag))
+			if _, ok := tag.Get("provide"); !ok {
+				continue
+			}
+			if err := fn(moduleType, field, tag); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // logf logs to b's Logger, if present.
 func (b *Binder) logf(fmt string, a ...interface{}) {
 	if b.logger != nil {
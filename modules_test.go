@@ -1,7 +1,26 @@
 package modules
 
 import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/go-modules/modules/inject"
+	"github.com/go-modules/modules/inject/env"
+	"github.com/go-modules/modules/inject/file"
+	injectFlag "github.com/go-modules/modules/inject/flag"
+	"github.com/go-modules/modules/inject/gnuflag"
+	"github.com/go-modules/modules/inject/secretfile"
+	"github.com/go-modules/modules/secret"
+	"github.com/go-modules/modules/tags"
 )
 
 // TestSimpleBind tests a one-way single-field binding.
@@ -95,6 +114,435 @@ func TestSingleton(t *testing.T) {
 	assertString(t, "testValue", moduleB.TestProvider())
 }
 
+type contextModule struct {
+	Field string `provide:"name,singleton" custom:"value" other:"otherValue"`
+}
+
+// TestFieldInjector tests that an inject.FieldInjector receives the context of the provided field.
+func TestFieldInjector(t *testing.T) {
+	var got inject.InjectionContext
+	injector := inject.FieldInjectorFunc(func(ctx inject.InjectionContext, value reflect.Value, tagValue string) (bool, error) {
+		got = ctx
+		other, _ := ctx.Tag().Get("other")
+		value.SetString(ctx.Field.Name + ":" + tagValue + ":" + other)
+		return true, nil
+	})
+	module := &contextModule{}
+
+	if err := NewBinder(Injectors{"custom": injector}).Bind(module); err != nil {
+		t.Fatal(err)
+	}
+
+	assertString(t, "Field:value:otherValue", module.Field)
+	assertString(t, "name", got.Name)
+	if !got.Options.Contains("singleton") {
+		t.Errorf("expected options to contain singleton: %q", got.Options)
+	}
+	if got.ModuleType != reflect.TypeOf(contextModule{}) {
+		t.Errorf("expected module type %s got %s", reflect.TypeOf(contextModule{}), got.ModuleType)
+	}
+}
+
+// TestDotEnv tests binding values from .env files, with and without precedence over the process environment.
+func TestDotEnv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "modules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, ".env")
+	if err := ioutil.WriteFile(path, []byte("DOTENV_TEST_HOST=dotenvHost\nDOTENV_TEST_PORT=8080\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("DOTENV_TEST_HOST", "envHost")
+	defer os.Unsetenv("DOTENV_TEST_HOST")
+
+	for _, testCase := range []struct {
+		override bool
+		host     string
+	}{
+		{false, "envHost"},
+		{true, "dotenvHost"},
+	} {
+		module := &struct {
+			Host string `provide:"host" env:"DOTENV_TEST_HOST"`
+			Port int    `provide:"port" dotenv:"DOTENV_TEST_PORT"`
+		}{}
+		binder := NewBinder(DotEnv{Paths: []string{path, filepath.Join(dir, "missing")}, Override: testCase.override})
+		if err := binder.Bind(module); err != nil {
+			t.Fatal(err)
+		}
+		assertString(t, testCase.host, module.Host)
+		if module.Port != 8080 {
+			t.Errorf("expected 8080 got %d", module.Port)
+		}
+	}
+
+	// Configured env injectors keep their options.
+	if err := ioutil.WriteFile(path, []byte("MYAPP_DOTENV_TEST_HOST=prefixedHost\nDOTENV_TEST_HOST=dotenvHost\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	prefixed := &struct {
+		Host string `provide:"host" env:"DOTENV_TEST_HOST"`
+	}{}
+	binder := NewBinder(DotEnv{Paths: []string{path}}, Injectors{"env": env.New(env.Prefix("MYAPP_"))})
+	if err := binder.Bind(prefixed); err != nil {
+		t.Fatal(err)
+	}
+	assertString(t, "prefixedHost", prefixed.Host)
+
+	// Disabled tag keys stay disabled, regardless of option order.
+	disabled := &struct {
+		Host string `provide:"host" env:"DOTENV_TEST_HOST" dotenv:"DOTENV_TEST_HOST"`
+	}{}
+	binder = NewBinder(DisableInjectors{"env", "dotenv"}, DotEnv{Paths: []string{path}})
+	if err := binder.Bind(disabled); err != nil {
+		t.Fatal(err)
+	}
+	assertString(t, "", disabled.Host)
+}
+
+// TestFlags tests defining and parsing command line flags from module tags.
+func TestFlags(t *testing.T) {
+	module := &struct {
+		Port    int    `provide:"port" flag:"port" literal:"80" usage:"the port to listen on"`
+		Host    string `provide:"host" flag:"host" literal:"localhost"`
+		Verbose bool   `provide:"verbose" flag:"verbose"`
+	}{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+
+	binder := NewBinder(Flags{FlagSet: fs, Args: []string{"-port", "8080", "-verbose", "arg"}})
+	if err := binder.Bind(module); err != nil {
+		t.Fatal(err)
+	}
+
+	if module.Port != 8080 {
+		t.Errorf("expected 8080 got %d", module.Port)
+	}
+	assertString(t, "localhost", module.Host)
+	if !module.Verbose {
+		t.Error("expected verbose to be set")
+	}
+	if fs.NArg() != 1 || fs.Arg(0) != "arg" {
+		t.Errorf("expected remaining args [arg] got %v", fs.Args())
+	}
+
+	var usage bytes.Buffer
+	fs.SetOutput(&usage)
+	fs.PrintDefaults()
+	if !strings.Contains(usage.String(), "the port to listen on (default 80)") {
+		t.Errorf("expected usage and default in %q", usage.String())
+	}
+
+	// Defaults of secret fields are redacted from usage.
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	secrets := &struct {
+		Password string `provide:"dbPassword,secret" flag:"db-password" literal:"hunter2"`
+	}{}
+	if err := NewBinder(Flags{FlagSet: fs, Args: []string{}}).Bind(secrets); err != nil {
+		t.Fatal(err)
+	}
+	assertString(t, "hunter2", secrets.Password)
+	assertString(t, secret.Redacted, fs.Lookup("db-password").DefValue)
+
+	// Invalid values fail binding.
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	if err := NewBinder(Flags{FlagSet: fs, Args: []string{"-port", "eighty"}}).Bind(module); err == nil {
+		t.Error("expected error parsing invalid flag value")
+	}
+}
+
+// TestFlagPrecedence tests that unset flags fall back to other tag keys, despite having default values.
+func TestFlagPrecedence(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Bool("verbose", false, "")
+	fs.String("host", "", "")
+	if err := fs.Parse([]string{"-host", ""}); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("FLAG_TEST_VERBOSE", "true")
+	defer os.Unsetenv("FLAG_TEST_VERBOSE")
+
+	module := &struct {
+		Verbose bool   `provide:"verbose" flag:"verbose" env:"FLAG_TEST_VERBOSE" literal:"false"`
+		Host    string `provide:"host" flag:"host" literal:"localhost"`
+	}{}
+	if err := NewBinder(Injectors{"flag": injectFlag.New(fs)}).Bind(module); err != nil {
+		t.Fatal(err)
+	}
+	if !module.Verbose {
+		t.Error("expected verbose to be set from the environment")
+	}
+	assertString(t, "", module.Host)
+}
+
+// TestGNUFlags tests defining and parsing GNU style command line flags from module tags.
+func TestGNUFlags(t *testing.T) {
+	module := &struct {
+		Host    string   `provide:"host" gnuflag:"db-host,short=d" literal:"localhost"`
+		Port    int      `provide:"port" gnuflag:"port" literal:"80"`
+		Verbose bool     `provide:"verbose" gnuflag:"verbose,short=v"`
+		Tags    []string `provide:"tags" gnuflag:"tag"`
+	}{}
+
+	fs := gnuflag.NewFlagSet([]string{"-v", "--port", "8080", "--tag", "a", "--tag", "b", "arg"})
+	if err := NewBinder(GNUFlags{fs}).Bind(module); err != nil {
+		t.Fatal(err)
+	}
+	assertString(t, "localhost", module.Host)
+	if module.Port != 8080 {
+		t.Errorf("expected 8080 got %d", module.Port)
+	}
+	if !module.Verbose {
+		t.Error("expected verbose to be set")
+	}
+	if !reflect.DeepEqual([]string{"a", "b"}, module.Tags) {
+		t.Errorf("expected [a b] got %v", module.Tags)
+	}
+	if !reflect.DeepEqual([]string{"arg"}, fs.Args()) {
+		t.Errorf("expected [arg] got %v", fs.Args())
+	}
+
+	// The Binder may be reused.
+	binder := NewBinder(GNUFlags{gnuflag.NewFlagSet([]string{"--port", "8080"})})
+	for i := 0; i < 2; i++ {
+		reused := &struct {
+			Port int `provide:"port" gnuflag:"port"`
+		}{}
+		if err := binder.Bind(reused); err != nil {
+			t.Fatal(err)
+		}
+		if reused.Port != 8080 {
+			t.Errorf("expected 8080 got %d", reused.Port)
+		}
+	}
+
+	args := &struct {
+		First string   `provide:"first" arg:"0"`
+		Rest  []string `provide:"rest" arg:"0..."`
+	}{}
+	if err := NewBinder(GNUFlags{gnuflag.NewFlagSet([]string{"-v", "a", "b"})}).Bind(module, args); err != nil {
+		t.Fatal(err)
+	}
+	assertString(t, "a", args.First)
+	if !reflect.DeepEqual([]string{"a", "b"}, args.Rest) {
+		t.Errorf("expected [a b] got %v", args.Rest)
+	}
+
+	fs = gnuflag.NewFlagSet([]string{"--unknown", "-x"})
+	err := NewBinder(GNUFlags{fs}).Bind(module)
+	if bindingErr, ok := err.(*BindingError); !ok {
+		t.Errorf("expected BindingError got %v", err)
+	} else if len(bindingErr.errs) != 2 {
+		t.Errorf("expected 2 errors got %d", len(bindingErr.errs))
+	}
+}
+
+// TestSecrets tests that secret values are injected, but redacted from logs.
+func TestSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "api_key"), []byte("secretFileValue\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("SECRET_TEST_TOKEN", "secretEnvValue")
+	defer os.Unsetenv("SECRET_TEST_TOKEN")
+
+	provider := &struct {
+		Password string             `provide:"password,secret" literal:"secretLiteralValue"`
+		Token    secret.String      `provide:"token" env:"SECRET_TEST_TOKEN"`
+		APIKey   string             `provide:"apiKey" secrets:"api_key"`
+		PIN      secret.Secret[int] `provide:"pin" literal:"1234"`
+	}{}
+	injected := &struct {
+		Password string             `inject:"password"`
+		Token    secret.String      `inject:"token"`
+		APIKey   string             `inject:"apiKey"`
+		PIN      secret.Secret[int] `inject:"pin"`
+	}{}
+
+	var log bytes.Buffer
+	// Secret injectors are recognized under any tag key.
+	binder := NewBinder(Logger{&log}, Injectors{"secrets": secretfile.New(dir)})
+	if err := binder.Bind(provider, injected); err != nil {
+		t.Fatal(err)
+	}
+
+	assertString(t, "secretLiteralValue", injected.Password)
+	assertString(t, "secretEnvValue", injected.Token.Reveal())
+	assertString(t, "secretFileValue", injected.APIKey)
+	if injected.PIN.Reveal() != 1234 {
+		t.Errorf("expected 1234 got %d", injected.PIN.Reveal())
+	}
+	for _, value := range []string{"secretLiteralValue", "secretEnvValue", "secretFileValue", "1234"} {
+		if strings.Contains(log.String(), value) {
+			t.Errorf("expected %q to be redacted from log:\n%s", value, log.String())
+		}
+	}
+	if !strings.Contains(log.String(), secret.Redacted) {
+		t.Errorf("expected redacted values in log:\n%s", log.String())
+	}
+}
+
+// TestValidation tests that violations of 'validate' tag rules across modules are reported together.
+func TestValidation(t *testing.T) {
+	server := &struct {
+		Port  int    `provide:"port" literal:"0" validate:"min=1,max=65535"`
+		Host  string `provide:"host" validate:"nonempty"`
+		Level string `provide:"level" literal:"info" validate:"oneof=debug|info|warn"`
+	}{}
+	client := &struct {
+		URL string `provide:"url" literal:"localhost" validate:"nonempty,url"`
+	}{}
+
+	err := NewBinder().Bind(server, client)
+	bindingErr, ok := err.(*BindingError)
+	if !ok {
+		t.Fatalf("expected BindingError got %v", err)
+	}
+	violations := 0
+	for _, err := range bindingErr.Errors() {
+		if validationErr, ok := err.(*ValidationError); !ok {
+			t.Errorf("expected ValidationError got %v", err)
+		} else {
+			violations += len(validationErr.Violations)
+		}
+	}
+	if len(bindingErr.Errors()) != 3 || violations != 3 {
+		t.Errorf("expected 3 violations of 3 fields got %s", bindingErr)
+	}
+
+	server.Host = "localhost"
+	client.URL = ""
+	valid := &struct {
+		URL string `provide:"url" literal:"http://localhost" validate:"nonempty,url"`
+	}{}
+	if err := NewBinder().Bind(server, valid); err == nil {
+		t.Error("expected error for invalid port")
+	}
+}
+
+// TestInjectorError tests that injector errors are reported, and that unprovided injections do not block binding.
+func TestInjectorError(t *testing.T) {
+	module := &struct {
+		Port     int    `provide:"port" literal:"eighty"`
+		Injected string `inject:"missing"`
+	}{}
+	err := NewBinder().Bind(module)
+	if bindingErr, ok := err.(*BindingError); !ok {
+		t.Errorf("expected BindingError got %v", err)
+	} else if len(bindingErr.Errors()) != 1 {
+		t.Errorf("expected 1 error got %s", bindingErr)
+	}
+}
+
+// TestRequired tests that all missing required values are reported together.
+func TestRequired(t *testing.T) {
+	os.Setenv("REQUIRED_TEST_USER", "user")
+	defer os.Unsetenv("REQUIRED_TEST_USER")
+	data := &struct {
+		Host     string `provide:"dbHost,required" flag:"db-host" env:"REQUIRED_TEST_HOST"`
+		User     string `provide:"dbUser,required" env:"REQUIRED_TEST_USER"`
+		Password string `provide:"dbPassword,required,secret" file:"password.txt"`
+		Name     string `provide:"dbName,required"`
+	}{Name: "preset"}
+	server := &struct {
+		Port int `provide:"port,required" env:"REQUIRED_TEST_PORT"`
+	}{}
+
+	err := NewBinder(Injectors{"file": inject.InjectorFunc(func(reflect.Value, string) (bool, error) {
+		return false, nil
+	})}).Bind(data, server)
+	bindingErr, ok := err.(*BindingError)
+	if !ok {
+		t.Fatalf("expected BindingError got %v", err)
+	}
+	missing := bindingErr.Missing()
+	if len(missing) != 3 {
+		t.Fatalf("expected 3 missing values got %s", bindingErr)
+	}
+	assertString(t, "{string|dbHost}", missing[0].Key)
+	if msg := missing[0].Error(); !strings.HasSuffix(msg, "tried: -db-host, env REQUIRED_TEST_HOST") {
+		t.Errorf("expected tried sources in %q", msg)
+	}
+	assertString(t, "{string|dbPassword}", missing[1].Key)
+	assertString(t, "{int|port}", missing[2].Key)
+
+	// Tried sources name the variables looked up by configured injectors.
+	err = NewBinder(Injectors{"env": env.New(env.Prefix("REQUIRED_TEST_"), env.AutoName(env.ScreamingSnake))}).Bind(&struct {
+		DBHost string `provide:"dbHost,required" env:""`
+		Port   int    `provide:"port,required" env:"PORT"`
+	}{})
+	if !errors.As(err, &bindingErr) {
+		t.Fatalf("expected BindingError got %v", err)
+	}
+	missing = bindingErr.Missing()
+	if len(missing) != 2 {
+		t.Fatalf("expected 2 missing values got %s", bindingErr)
+	}
+	if !reflect.DeepEqual([]Source{{"env", "REQUIRED_TEST_DB_HOST"}}, missing[0].Tried) {
+		t.Errorf("unexpected tried sources %+v", missing[0].Tried)
+	}
+	if !reflect.DeepEqual([]Source{{"env", "REQUIRED_TEST_PORT"}}, missing[1].Tried) {
+		t.Errorf("unexpected tried sources %+v", missing[1].Tried)
+	}
+}
+
+// TestInterpolation tests interpolation of environment variables and provided values in tag values.
+func TestInterpolation(t *testing.T) {
+	os.Setenv("INTERPOLATION_TEST_DIR", "/etc/app")
+	defer os.Unsetenv("INTERPOLATION_TEST_DIR")
+	server := &struct {
+		Host string `provide:"host" literal:"localhost"`
+		Port int    `provide:"port" literal:"8080"`
+	}{}
+	client := &struct {
+		URL    string `provide:"url" literal:"http://${host}:${port}/"`
+		Config string `provide:"config" literal:"${INTERPOLATION_TEST_DIR}/db.json"`
+		Cache  string `provide:"cache" literal:"${INTERPOLATION_TEST_CACHE:-/tmp}/cache"`
+		Price  string `provide:"price" literal:"$$5 $"`
+	}{}
+
+	if err := NewBinder().Bind(server, client); err != nil {
+		t.Fatal(err)
+	}
+	assertString(t, "http://localhost:8080/", client.URL)
+	assertString(t, "/etc/app/db.json", client.Config)
+	assertString(t, "/tmp/cache", client.Cache)
+	assertString(t, "$5 $", client.Price)
+
+	undefined := &struct {
+		Field string `provide:"field" literal:"${INTERPOLATION_TEST_UNDEFINED}"`
+	}{}
+	if err := NewBinder().Bind(undefined); err == nil {
+		t.Error("expected error for undefined reference")
+	}
+
+	secrets := &struct {
+		Password string `provide:"dbPassword,secret" literal:"hunter2"`
+		DSN      string `provide:"dsn" literal:"postgres://u:${dbPassword}@h"`
+	}{}
+	if err := NewBinder().Bind(secrets); err == nil {
+		t.Error("expected error for reference to secret value")
+	}
+	assertString(t, "", secrets.DSN)
+
+	ambiguous := &struct {
+		Int   int     `provide:"n" literal:"1"`
+		Float float64 `provide:"n" literal:"2"`
+		Ref   string  `provide:"ref" literal:"${n}"`
+	}{}
+	err := NewBinder().Bind(ambiguous)
+	if err == nil || !strings.Contains(err.Error(), "ambiguous reference ${n} to values of types float64, int") {
+		t.Errorf("expected error for ambiguous reference but got %v", err)
+	}
+}
+
 func assertNotNil(t *testing.T, value interface{}) {
 	if value == nil {
 		t.Errorf("expected non-nil value")
@@ -106,3 +554,141 @@ func assertString(t *testing.T, expected, got string) {
 		t.Errorf("expected %q got %q", expected, got)
 	}
 }
+
+func TestClose(t *testing.T) {
+	dir, err := ioutil.TempDir("", "modules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.txt")
+	if err := ioutil.WriteFile(path, []byte("line1\nline2"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	module := &struct {
+		File    *os.File       `provide:"file" file:"${path}"`
+		Reader  io.Reader      `provide:"reader" file:"${path}"`
+		Scanner *bufio.Scanner `provide:"scanner" file:"${path}"`
+		Bytes   []byte         `provide:"bytes" file:"${path}"`
+	}{}
+	pathModule := &struct {
+		Path string `provide:"path"`
+	}{path}
+	binder := NewBinder()
+	if err := binder.Bind(pathModule, module); err != nil {
+		t.Fatal(err)
+	}
+	assertString(t, "line1\nline2", string(module.Bytes))
+	if bytes, err := ioutil.ReadAll(module.Reader); err != nil {
+		t.Error(err)
+	} else {
+		assertString(t, "line1\nline2", string(bytes))
+	}
+	if !module.Scanner.Scan() {
+		t.Error(module.Scanner.Err())
+	} else {
+		assertString(t, "line1", module.Scanner.Text())
+	}
+
+	if err := binder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := module.File.Stat(); err == nil {
+		t.Error("expected file to be closed")
+	}
+	if _, err := module.Reader.Read(make([]byte, 1)); err == nil {
+		t.Error("expected reader to be closed")
+	}
+	if err := binder.Close(); err != nil {
+		t.Errorf("expected no error closing again, but got %s", err)
+	}
+
+	// Files are closed when binding fails.
+	failed := &struct {
+		File    *os.File `provide:"file" file:"${path}"`
+		Invalid int      `provide:"invalid" literal:"x"`
+	}{}
+	if err := binder.Bind(pathModule, failed); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := failed.File.Stat(); err == nil {
+		t.Error("expected file to be closed after failed binding")
+	}
+}
+
+func TestOptionalFile(t *testing.T) {
+	module := &struct {
+		Level string `provide:"level" file:"missing.txt,optional" literal:"info"`
+	}{}
+	if err := NewBinder().Bind(module); err != nil {
+		t.Fatal(err)
+	}
+	assertString(t, "info", module.Level)
+}
+
+func TestDisableInjectors(t *testing.T) {
+	module := &struct {
+		Value string `provide:"value" file:"/nonexistent/value.txt" literal:"default"`
+	}{}
+	if err := NewBinder().Bind(module); err == nil {
+		t.Error("expected error for missing file")
+	}
+	if err := NewBinder(DisableInjectors{"file"}).Bind(module); err != nil {
+		t.Fatal(err)
+	}
+	assertString(t, "default", module.Value)
+}
+
+func TestChecksumError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "modules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "model.txt")
+	if err := ioutil.WriteFile(path, []byte("model"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	module := &struct {
+		Model string `provide:"model" file:"${path},sha256=0000000000000000000000000000000000000000000000000000000000000000"`
+	}{}
+	pathModule := &struct {
+		Path string `provide:"path"`
+	}{path}
+
+	err = NewBinder().Bind(pathModule, module)
+	if _, ok := err.(*BindingError); !ok {
+		t.Fatalf("expected *BindingError but got %v", err)
+	}
+	var checksumErr *file.ChecksumError
+	if !errors.As(err, &checksumErr) {
+		t.Fatalf("expected *file.ChecksumError but got %v", err)
+	}
+	assertString(t, path, checksumErr.File)
+}
+
+func TestTagSyntaxError(t *testing.T) {
+	// Built with reflect, as go vet rejects malformed tags in source.
+	moduleType := reflect.StructOf([]reflect.StructField{
+		{Name: "Value", Type: reflect.TypeOf(""), Tag: `provide:"value" literal:"value`},
+		{Name: "Other", Type: reflect.TypeOf(""), Tag: `provide:"other" literal:"other"`},
+	})
+	module := reflect.New(moduleType)
+
+	err := NewBinder().Bind(module.Interface())
+	if _, ok := err.(*BindingError); !ok {
+		t.Fatalf("expected *BindingError but got %v", err)
+	}
+	var syntaxErr *tags.TagSyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("expected *tags.TagSyntaxError but got %v", err)
+	}
+	if !strings.HasSuffix(syntaxErr.Field, ".Value") {
+		t.Errorf("expected field Value but got %s", syntaxErr.Field)
+	}
+	if syntaxErr.Offset != 24 {
+		t.Errorf("expected offset 24 but got %d", syntaxErr.Offset)
+	}
+	assertString(t, "other", module.Elem().Field(1).String())
+}
@@ -0,0 +1,171 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/go-modules/modules/inject"
+	"github.com/go-modules/modules/secret"
+	"github.com/go-modules/modules/tags"
+)
+
+// Sources of provided values which were not set by an inject.Injector.
+const (
+	// SourcePreset indicates a value set prior to binding.
+	SourcePreset = "preset"
+	// SourceProvider indicates a value set by a module's Provide() method.
+	SourceProvider = "provider"
+	// SourceNone indicates a zero value which was never set.
+	SourceNone = "none"
+)
+
+// A ProvidedValue describes a value provided during binding, and where it came from.
+type ProvidedValue struct {
+	// The module type declaring the field.
+	Module string `json:"module"`
+	// The field name.
+	Field string `json:"field"`
+	// The name the value is provided as.
+	Name string `json:"name"`
+	// The value type.
+	Type string `json:"type"`
+	// The tag key of the inject.Injector which set the value, or one of SourcePreset, SourceProvider or SourceNone.
+	Source string `json:"source"`
+	// The tag value passed to the inject.Injector which set the value, if any, e.g. an environment variable name.
+	TagValue string `json:"tagValue,omitempty"`
+	// Whether the value is secret, i.e. tagged with the 'secret' option, of a secret.Value type, or set by an
+	// inject.SecretInjector such as the 'secretfile' injector.
+	Secret bool `json:"secret,omitempty"`
+	// The formatted value, or a redaction placeholder for secrets.
+	Value string `json:"value"`
+}
+
+// ProvideHook is a functional option which adds a hook to be called for each value provided during binding.
+// Hooks may be called concurrently.
+type ProvideHook func(ProvidedValue)
+
+func (h ProvideHook) configure(b *Binder) {
+	b.hooks = append(b.hooks, h)
+}
+
+// An EffectiveConfig records the values provided during binding, and where they came from.
+// It is also a functional option, which configures a Binder to record into it.
+type EffectiveConfig struct {
+	sync.Mutex
+	// The provided values, in the order they were provided, including reloaded values.
+	Values []ProvidedValue
+}
+
+func (c *EffectiveConfig) configure(b *Binder) {
+	b.hooks = append(b.hooks, c.record)
+}
+
+// record appends v.
+func (c *EffectiveConfig) record(v ProvidedValue) {
+	c.Lock()
+	c.Values = append(c.Values, v)
+	c.Unlock()
+}
+
+// Lookup returns the most recently recorded value provided as name, with type name typeName (e.g. "string"), so that
+// reloaded values supersede earlier ones.
+func (c *EffectiveConfig) Lookup(name, typeName string) (ProvidedValue, bool) {
+	c.Lock()
+	defer c.Unlock()
+	for i := len(c.Values) - 1; i >= 0; i-- {
+		if v := c.Values[i]; v.Name == name && v.Type == typeName {
+			return v, true
+		}
+	}
+	return ProvidedValue{}, false
+}
+
+// WriteJSON writes the recorded values as a JSON array.
+func (c *EffectiveConfig) WriteJSON(w io.Writer) error {
+	c.Lock()
+	defer c.Unlock()
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c.Values)
+}
+
+// newProvidedValue describes value, provided from source.
+func newProvidedValue(ctx inject.InjectionContext, value reflect.Value, source, tagValue string, redact bool) ProvidedValue {
+	v := ProvidedValue{
+		Module:   typeName(ctx.ModuleType),
+		Field:    ctx.Field.Name,
+		Name:     ctx.Name,
+		Type:     value.Type().String(),
+		Source:   source,
+		TagValue: tagValue,
+		Secret:   redact,
+		Value:    format(value, redact),
+	}
+	// Literal tag values are the secret itself.
+	if redact && source == "literal" {
+		v.TagValue = secret.Redacted
+	}
+	return v
+}
+
+// format formats value for logs and hooks, or returns a redaction placeholder if redact is true.
+func format(value reflect.Value, redact bool) string {
+	if redact {
+		return secret.Redacted
+	}
+	if !value.CanInterface() {
+		return "<unexported>"
+	}
+	return fmt.Sprintf("%v", value.Interface())
+}
+
+// isSecret returns true if value's type implements secret.Value.
+func isSecret(value reflect.Value) bool {
+	return isSecretType(value.Type())
+}
+
+// isSecretType returns true if values of typ implement secret.Value.
+func isSecretType(typ reflect.Type) bool {
+	return typ.Implements(typeOfSecret) || reflect.PtrTo(typ).Implements(typeOfSecret)
+}
+
+// isSecretField returns true if field is provided with the 'secret' option, or is of a secret type.
+func isSecretField(field reflect.StructField, options tags.TagOptions) bool {
+	return options.Contains("secret") || isSecretType(field.Type)
+}
+
+var typeOfSecret = reflect.TypeOf((*secret.Value)(nil)).Elem()
+
+// presetSource returns the source of value prior to injection, given a copy from before calling Provide(), or an
+// invalid Value if there was no call.
+func presetSource(before, value reflect.Value) string {
+	if before.IsValid() && changed(before, value) {
+		return SourceProvider
+	}
+	if value.IsZero() {
+		return SourceNone
+	}
+	return SourcePreset
+}
+
+// snapshot returns a copy of value, or an invalid Value if value cannot be copied.
+func snapshot(value reflect.Value) reflect.Value {
+	if !value.CanInterface() {
+		return reflect.Value{}
+	}
+	c := reflect.New(value.Type()).Elem()
+	c.Set(value)
+	return c
+}
+
+// changed returns true if value differs from before.
+func changed(before, value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Func, reflect.Chan, reflect.Map, reflect.Ptr, reflect.Slice, reflect.UnsafePointer:
+		return before.Pointer() != value.Pointer() || value.Kind() == reflect.Slice && before.Len() != value.Len()
+	}
+	return !reflect.DeepEqual(before.Interface(), value.Interface())
+}
@@ -0,0 +1,93 @@
+package modules
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/go-modules/modules/secret"
+)
+
+type provenanceModule struct {
+	Host     string `provide:"host" env:"PROVENANCE_TEST_HOST" literal:"localhost"`
+	Port     int    `provide:"port" env:"PROVENANCE_TEST_PORT" literal:"80"`
+	Preset   string `provide:"preset"`
+	Provided string `provide:"provided"`
+	Unset    string `provide:"unset"`
+	Password string `provide:"password,secret" literal:"hunter2"`
+}
+
+func (m *provenanceModule) Provide() error {
+	m.Provided = "provided"
+	return nil
+}
+
+// TestEffectiveConfig tests recording where provided values came from.
+func TestEffectiveConfig(t *testing.T) {
+	os.Setenv("PROVENANCE_TEST_PORT", "8080")
+	defer os.Unsetenv("PROVENANCE_TEST_PORT")
+
+	config := &EffectiveConfig{}
+	if err := NewBinder(config).Bind(&provenanceModule{Preset: "preset"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []ProvidedValue{
+		{Name: "host", Type: "string", Source: "literal", TagValue: "localhost", Value: "localhost"},
+		{Name: "port", Type: "int", Source: "env", TagValue: "PROVENANCE_TEST_PORT", Value: "8080"},
+		{Name: "preset", Type: "string", Source: SourcePreset, Value: "preset"},
+		{Name: "provided", Type: "string", Source: SourceProvider, Value: "provided"},
+		{Name: "unset", Type: "string", Source: SourceNone},
+		{Name: "password", Type: "string", Source: "literal", TagValue: secret.Redacted, Secret: true, Value: secret.Redacted},
+	} {
+		got, ok := config.Lookup(expected.Name, expected.Type)
+		if !ok {
+			t.Errorf("expected value for %s", expected.Name)
+			continue
+		}
+		expected.Module = "modules.provenanceModule"
+		expected.Field = got.Field
+		if got != expected {
+			t.Errorf("expected %+v got %+v", expected, got)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := config.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte(`"hunter2"`)) {
+		t.Error("expected secret value to be secret.Redacted")
+	}
+	var decoded []ProvidedValue
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 6 {
+		t.Errorf("expected 6 values got %d", len(decoded))
+	}
+}
+
+// TestEffectiveConfigReload tests that lookups return reloaded values.
+func TestEffectiveConfigReload(t *testing.T) {
+	t.Setenv("PROVENANCE_TEST_LEVEL", "info")
+	config := &EffectiveConfig{}
+	binder := NewBinder(config)
+	defer binder.Close()
+	if err := binder.Bind(&struct {
+		Level string `provide:"level,reload" env:"PROVENANCE_TEST_LEVEL"`
+	}{}); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("PROVENANCE_TEST_LEVEL", "debug")
+	if err := binder.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := config.Lookup("level", "string"); !ok {
+		t.Error("expected value for level")
+	} else if got.Value != "debug" {
+		t.Errorf("expected reloaded value %q got %q", "debug", got.Value)
+	}
+}
@@ -0,0 +1,259 @@
+package modules
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-modules/modules/inject"
+)
+
+// A Reloadable module is notified when values it injects are reloaded.
+// Values provided with the 'reload' option are reloaded by Binder.Reload(), or automatically with AutoReload.
+type Reloadable interface {
+	// Reload is called with the name and new value of each reloaded value which the module injects.
+	Reload(name string, value interface{})
+}
+
+// AutoReload is a functional option which reloads values provided with the 'reload' option every Interval (if
+// positive), and on each of Signals (e.g. syscall.SIGHUP), starting after the first successful call to Bind and until
+// the Binder is closed. Reload errors are logged.
+type AutoReload struct {
+	Interval time.Duration
+	Signals  []os.Signal
+}
+
+func (a AutoReload) configure(b *Binder) {
+	b.autoReload = &a
+}
+
+// Reload reloads values provided with the 'reload' option by successful calls to Bind, by re-running the
+// inject.Injectors for their original tags. Changed values are delivered to injected getters (func() T) and channels
+// (<-chan T), and to Reloadable modules which inject them. Module fields are not modified.
+// Values which fail to reload keep their previous values, and the failures are returned in a *BindingError.
+func (b *Binder) Reload() error {
+	b.reloads.Lock()
+	defer b.reloads.Unlock()
+	// Files are read again, rather than reusing documents parsed while binding.
+	cache := &sync.Map{}
+	var errs []error
+	for _, r := range b.reloads.values {
+		if err := r.reload(cache); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return &BindingError{errs}
+	}
+	return nil
+}
+
+// A reloadable holds a value provided with the 'reload' option, and where to deliver new values.
+type reloadable struct {
+	// The binding which provided the value, to re-run its injectors.
+	binding *binding
+	ctx     inject.InjectionContext
+	key     bindKey
+	secret  bool
+
+	sync.RWMutex
+	current reflect.Value
+	// Resources opened for the current value, tracked by the Binder as one resource.
+	handles *closers
+	// Channels of the value's type, to send new values.
+	channels []reflect.Value
+	// Reloadable modules which inject the value.
+	listeners []Reloadable
+}
+
+// get returns the current value.
+func (r *reloadable) get() reflect.Value {
+	r.RLock()
+	defer r.RUnlock()
+	return r.current
+}
+
+// subscribe returns a new channel of the value's type, to receive new values.
+func (r *reloadable) subscribe() reflect.Value {
+	r.Lock()
+	defer r.Unlock()
+	ch := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, r.key.Type), 1)
+	r.channels = append(r.channels, ch)
+	return ch.Convert(reflect.ChanOf(reflect.RecvDir, r.key.Type))
+}
+
+// reload re-runs the injectors for r's tags, and delivers the new value if it has changed.
+// Keeps the current value if no injector sets a value. Resources opened for the value which is not kept are closed.
+func (r *reloadable) reload(cache *sync.Map) error {
+	ctx := r.ctx
+	ctx.Cache = cache
+	opened := &closers{}
+	ctx.OnClose = opened.track
+	value := reflect.New(r.key.Type).Elem()
+	source, tagValue, _, err := r.binding.injectTags(ctx, value)
+	if err == nil && source != "" {
+		err = validateTag(ctx, r.key, value)
+	}
+	if err != nil || source == "" {
+		r.closeAll(opened.take())
+		return err
+	}
+
+	r.Lock()
+	if reflect.DeepEqual(r.current.Interface(), value.Interface()) {
+		r.Unlock()
+		r.closeAll(opened.take())
+		return nil
+	}
+	r.current = value
+	previous := r.handles.take()
+	for _, closer := range opened.take() {
+		r.handles.track(closer)
+	}
+	channels := append([]reflect.Value(nil), r.channels...)
+	listeners := append([]Reloadable(nil), r.listeners...)
+	r.Unlock()
+	r.closeAll(previous)
+
+	// Later interpolations refer to the new value.
+	r.binding.fields.bind(r.key, bound{value: value, secret: r.secret})
+	r.binding.logf("reloaded %s -> %s\n", format(value, r.secret), r.key.String())
+	r.binding.callHooks(ctx, value, source, tagValue, r.secret)
+
+	for _, ch := range channels {
+		// Replace any undelivered value.
+		ch.TryRecv()
+		ch.TrySend(value)
+	}
+	for _, listener := range listeners {
+		listener.Reload(r.key.name, value.Interface())
+	}
+	return nil
+}
+
+// closeAll closes resources which no longer back r's value, and logs any errors.
+func (r *reloadable) closeAll(list []io.Closer) {
+	for _, err := range closeAll(list) {
+		r.binding.logf("failed to close resource: %s\n", err)
+	}
+}
+
+// provideReloadable binds a getter (func() T) and channels (<-chan T) for a value provided with the 'reload' option,
+// and registers it to be reloaded after successful binding. Handles holds the resources opened for value.
+func (b *binding) provideReloadable(ctx inject.InjectionContext, key bindKey, value reflect.Value, secret bool, handles *closers) {
+	current := reflect.New(key.Type).Elem()
+	current.Set(value)
+	r := &reloadable{binding: b, ctx: ctx, key: key, secret: secret, current: current, handles: handles}
+	b.reloadables = append(b.reloadables, r)
+
+	getterType := reflect.FuncOf(nil, []reflect.Type{key.Type}, false)
+	getter := reflect.MakeFunc(getterType, func([]reflect.Value) []reflect.Value {
+		return []reflect.Value{r.get()}
+	})
+	getterKey := bindKey{getterType, key.name}
+	b.fields.bind(getterKey, bound{value: getter, secret: secret, derived: true})
+	close(b.gates.get(getterKey))
+
+	chanKey := bindKey{reflect.ChanOf(reflect.RecvDir, key.Type), key.name}
+	b.fields.bind(chanKey, bound{secret: secret, newValue: r.subscribe, derived: true})
+	close(b.gates.get(chanKey))
+}
+
+// addListeners registers Reloadable modules to be notified of reloaded values which they inject.
+func (b *binding) addListeners(modules []interface{}) {
+	for _, module := range modules {
+		listener, ok := module.(Reloadable)
+		if !ok {
+			continue
+		}
+		moduleType := reflect.TypeOf(module).Elem()
+		for i := 0; i < moduleType.NumField(); i++ {
+			field := moduleType.Field(i)
+			name, ok := field.Tag.Lookup("inject")
+			if !ok {
+				continue
+			}
+			for _, r := range b.reloadables {
+				if r.key == (bindKey{field.Type, name}) {
+					r.Lock()
+					r.listeners = append(r.listeners, listener)
+					r.Unlock()
+				}
+			}
+		}
+	}
+}
+
+// A reloads instance holds the reloadable values of successful bindings.
+type reloads struct {
+	sync.Mutex
+	values []*reloadable
+}
+
+// add registers values to be reloaded.
+func (r *reloads) add(values []*reloadable) {
+	r.Lock()
+	r.values = append(r.values, values...)
+	r.Unlock()
+}
+
+// close closes the channels of reloadable values, and discards them.
+func (r *reloads) close() {
+	r.Lock()
+	defer r.Unlock()
+	for _, value := range r.values {
+		value.Lock()
+		for _, ch := range value.channels {
+			ch.Close()
+		}
+		value.channels = nil
+		value.Unlock()
+	}
+	r.values = nil
+}
+
+// startAutoReload starts reloading values as configured by AutoReload, until the Binder is closed.
+func (b *Binder) startAutoReload() {
+	a := b.autoReload
+	signals := make(chan os.Signal, 1)
+	if len(a.Signals) > 0 {
+		signal.Notify(signals, a.Signals...)
+	}
+	var ticker *time.Ticker
+	var tick <-chan time.Time
+	if a.Interval > 0 {
+		ticker = time.NewTicker(a.Interval)
+		tick = ticker.C
+	}
+	go func() {
+		defer signal.Stop(signals)
+		if ticker != nil {
+			defer ticker.Stop()
+		}
+		for {
+			select {
+			case <-b.stopReload:
+				return
+			case <-tick:
+			case sig := <-signals:
+				b.logf("reloading on signal %s\n", sig)
+			}
+			if err := b.Reload(); err != nil {
+				b.logf("failed to reload: %s\n", err)
+			}
+		}
+	}()
+}
+
+// stopAutoReload stops automatic reloading, if started, and prevents it from starting.
+func (b *Binder) stopAutoReload() {
+	b.startReload.Do(func() {})
+	select {
+	case <-b.stopReload:
+	default:
+		close(b.stopReload)
+	}
+}
@@ -0,0 +1,222 @@
+package modules
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-modules/modules/inject"
+)
+
+type reloadListener struct {
+	Level string `inject:"level"`
+
+	sync.Mutex
+	reloaded map[string]interface{}
+}
+
+func (l *reloadListener) Reload(name string, value interface{}) {
+	l.Lock()
+	l.reloaded[name] = value
+	l.Unlock()
+}
+
+func TestReload(t *testing.T) {
+	t.Setenv("RELOAD_TEST_LEVEL", "info")
+	provider := &struct {
+		Level string `provide:"level,reload" env:"RELOAD_TEST_LEVEL" validate:"oneof=debug|info|warn"`
+		Fixed string `provide:"fixed" env:"RELOAD_TEST_LEVEL"`
+	}{}
+	consumer := &struct {
+		Level   string        `inject:"level"`
+		Get     func() string `inject:"level"`
+		Changes <-chan string `inject:"level"`
+		Other   <-chan string `inject:"level"`
+	}{}
+	listener := &reloadListener{reloaded: make(map[string]interface{})}
+	var provided []ProvidedValue
+	binder := NewBinder(ProvideHook(func(v ProvidedValue) {
+		provided = append(provided, v)
+	}))
+	if err := binder.Bind(provider, consumer, listener); err != nil {
+		t.Fatal(err)
+	}
+	assertString(t, "info", consumer.Get())
+
+	// Unchanged values are not delivered.
+	if err := binder.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case v := <-consumer.Changes:
+		t.Errorf("unexpected value %q", v)
+	default:
+	}
+
+	os.Setenv("RELOAD_TEST_LEVEL", "debug")
+	if err := binder.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	assertString(t, "debug", consumer.Get())
+	assertString(t, "debug", <-consumer.Changes)
+	assertString(t, "debug", <-consumer.Other)
+	if listener.reloaded["level"] != "debug" {
+		t.Errorf("expected listener to be notified, but got %v", listener.reloaded)
+	}
+	if last := provided[len(provided)-1]; last.Name != "level" || last.Value != "debug" {
+		t.Errorf("expected hook to be called with reloaded value, but got %+v", last)
+	}
+	// Fields are not modified.
+	assertString(t, "info", provider.Level)
+	assertString(t, "info", consumer.Level)
+	assertString(t, "info", listener.Level)
+
+	// Invalid values are not reloaded.
+	os.Setenv("RELOAD_TEST_LEVEL", "verbose")
+	if err := binder.Reload(); err == nil {
+		t.Error("expected validation error")
+	}
+	assertString(t, "debug", consumer.Get())
+
+	// Unset values are kept.
+	os.Unsetenv("RELOAD_TEST_LEVEL")
+	if err := binder.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	assertString(t, "debug", consumer.Get())
+
+	if err := binder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-consumer.Changes; ok {
+		t.Error("expected channel to be closed")
+	}
+}
+
+// A testHandle is a resource which counts the handles open.
+type testHandle struct {
+	Name string
+	open *int
+}
+
+func (h *testHandle) Close() error {
+	*h.open--
+	return nil
+}
+
+func TestReloadHandles(t *testing.T) {
+	open, name := 0, "a"
+	injector := inject.FieldInjectorFunc(func(ctx inject.InjectionContext, value reflect.Value, _ string) (bool, error) {
+		h := &testHandle{name, &open}
+		open++
+		ctx.Track(h)
+		value.Set(reflect.ValueOf(h))
+		return true, nil
+	})
+	provider := &struct {
+		Handle *testHandle `provide:"handle,reload" handle:""`
+	}{}
+	binder := NewBinder(Injectors{"handle": injector})
+	if err := binder.Bind(provider); err != nil {
+		t.Fatal(err)
+	}
+	if open != 1 {
+		t.Fatalf("expected 1 open handle but got %d", open)
+	}
+
+	// Handles for unchanged values are closed.
+	for i := 0; i < 3; i++ {
+		if err := binder.Reload(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if open != 1 {
+		t.Errorf("expected 1 open handle but got %d", open)
+	}
+
+	// Handles for replaced values are closed.
+	for _, name = range []string{"b", "c"} {
+		if err := binder.Reload(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if open != 1 {
+		t.Errorf("expected 1 open handle but got %d", open)
+	}
+	if tracked := len(binder.closers.list); tracked != 1 {
+		t.Errorf("expected 1 tracked closer but got %d", tracked)
+	}
+
+	if err := binder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if open != 0 {
+		t.Errorf("expected 0 open handles but got %d", open)
+	}
+}
+
+func TestReloadInterpolation(t *testing.T) {
+	// Reloadable values are also bound as getters and channels, which references by name must not resolve to.
+	for i := 0; i < 20; i++ {
+		module := &struct {
+			Port int    `provide:"port,reload" literal:"8080"`
+			URL  string `provide:"url" literal:"http://h:${port}"`
+		}{}
+		if err := NewBinder().Bind(module); err != nil {
+			t.Fatal(err)
+		}
+		assertString(t, "http://h:8080", module.URL)
+	}
+}
+
+func TestAutoReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "modules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(path, []byte(`{"port": 8080}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	type config struct {
+		Port int `json:"port"`
+	}
+	provider := &struct {
+		Path   string  `provide:"path"`
+		Config *config `provide:"config,reload" file:"${path}"`
+		Port   int     `provide:"port,reload" file:"${path}#/port"`
+	}{Path: path}
+	consumer := &struct {
+		Config  func() *config `inject:"config"`
+		Changes <-chan int     `inject:"port"`
+	}{}
+	binder := NewBinder(AutoReload{Interval: 10 * time.Millisecond})
+	if err := binder.Bind(provider, consumer); err != nil {
+		t.Fatal(err)
+	}
+	defer binder.Close()
+	if port := consumer.Config().Port; port != 8080 {
+		t.Errorf("expected port 8080 but got %d", port)
+	}
+
+	if err := ioutil.WriteFile(path, []byte(`{"port": 9090}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case port := <-consumer.Changes:
+		if port != 9090 {
+			t.Errorf("expected port 9090 but got %d", port)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reload")
+	}
+	if port := consumer.Config().Port; port != 9090 {
+		t.Errorf("expected port 9090 but got %d", port)
+	}
+}
@@ -0,0 +1,36 @@
+//go:build unix
+
+package modules
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReloadOnSignal(t *testing.T) {
+	t.Setenv("RELOAD_TEST_SIGNAL", "a")
+	provider := &struct {
+		Value string `provide:"value,reload" env:"RELOAD_TEST_SIGNAL"`
+	}{}
+	consumer := &struct {
+		Changes <-chan string `inject:"value"`
+	}{}
+	binder := NewBinder(AutoReload{Signals: []os.Signal{syscall.SIGUSR1}})
+	if err := binder.Bind(provider, consumer); err != nil {
+		t.Fatal(err)
+	}
+	defer binder.Close()
+
+	os.Setenv("RELOAD_TEST_SIGNAL", "b")
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case v := <-consumer.Changes:
+		assertString(t, "b", v)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reload")
+	}
+}
@@ -0,0 +1,66 @@
+package modules
+
+import (
+	"io"
+	"sync"
+)
+
+// Close closes resources opened while binding, such as files injected into *os.File, io.Reader and *bufio.Scanner
+// fields, in the reverse order they were opened. Resources from failed calls to Bind are closed when Bind returns.
+// Also stops automatic reloading, and closes channels of reloaded values.
+// Returns a *CloseError holding any errors from closing resources.
+func (b *Binder) Close() error {
+	b.stopAutoReload()
+	b.reloads.close()
+	if errs := b.closers.close(); len(errs) > 0 {
+		return &CloseError{errs}
+	}
+	return nil
+}
+
+// A closers instance holds resources to close.
+type closers struct {
+	sync.Mutex
+	list []io.Closer
+}
+
+// track adds c to the resources to close.
+func (c *closers) track(closer io.Closer) {
+	c.Lock()
+	c.list = append(c.list, closer)
+	c.Unlock()
+}
+
+// take removes and returns the tracked resources.
+func (c *closers) take() []io.Closer {
+	c.Lock()
+	list := c.list
+	c.list = nil
+	c.Unlock()
+	return list
+}
+
+// close closes the tracked resources in reverse order, and returns any errors.
+func (c *closers) close() []error {
+	return closeAll(c.take())
+}
+
+// Close implements io.Closer by closing the tracked resources, so that they may be tracked as one resource.
+// Returns a *CloseError holding any errors from closing resources.
+func (c *closers) Close() error {
+	if errs := c.close(); len(errs) > 0 {
+		return &CloseError{errs}
+	}
+	return nil
+}
+
+// closeAll closes list in reverse order, and returns any errors.
+func closeAll(list []io.Closer) []error {
+	var errs []error
+	for i := len(list) - 1; i >= 0; i-- {
+		if err := list[i].Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
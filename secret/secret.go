@@ -0,0 +1,84 @@
+// Package secret provides wrapper types for secret values, which are redacted when formatted or marshalled.
+// Binders redact provided values of these types from logs, hooks and effective configuration dumps.
+package secret
+
+import (
+	"reflect"
+
+	"github.com/go-modules/modules/inject/literal"
+)
+
+// Redacted replaces secret values when formatted.
+const Redacted = "[REDACTED]"
+
+// A Value is a secret value. Binders redact provided values implementing Value.
+type Value interface {
+	// Secret is a marker method.
+	Secret()
+}
+
+// A String is a secret string.
+type String string
+
+// Secret implements Value.
+func (String) Secret() {}
+
+// Reveal returns the secret string.
+func (s String) Reveal() string {
+	return string(s)
+}
+
+// String returns Redacted, so that the secret is not formatted by the fmt package.
+func (String) String() string {
+	return Redacted
+}
+
+// GoString returns Redacted, so that the secret is not formatted by the fmt package.
+func (String) GoString() string {
+	return Redacted
+}
+
+// MarshalText returns Redacted, so that the secret is not marshalled by encoding packages.
+func (String) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// A Secret holds a secret value of type T.
+// Secrets may be set from strings by literal.Injector, and therefore the env, flag and file injectors as well.
+type Secret[T any] struct {
+	value T
+}
+
+// New returns a Secret holding value.
+func New[T any](value T) Secret[T] {
+	return Secret[T]{value}
+}
+
+// Secret implements Value.
+func (Secret[T]) Secret() {}
+
+// Reveal returns the secret value.
+func (s Secret[T]) Reveal() T {
+	return s.value
+}
+
+// String returns Redacted, so that the secret is not formatted by the fmt package.
+func (Secret[T]) String() string {
+	return Redacted
+}
+
+// GoString returns Redacted, so that the secret is not formatted by the fmt package.
+func (Secret[T]) GoString() string {
+	return Redacted
+}
+
+// MarshalText returns Redacted, so that the secret is not marshalled by encoding packages.
+func (Secret[T]) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// UnmarshalText parses text into the secret value via literal.Injector.
+func (s *Secret[T]) UnmarshalText(text []byte) error {
+	_, err := literal.Injector.Inject(reflect.ValueOf(&s.value).Elem(), string(text))
+	return err
+}
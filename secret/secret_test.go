@@ -0,0 +1,52 @@
+package secret
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-modules/modules/inject/literal"
+)
+
+func TestString(t *testing.T) {
+	s := String("hunter2")
+	for _, formatted := range []string{fmt.Sprint(s), fmt.Sprintf("%v %s %#v", s, s, s), fmt.Sprintf("%+v", struct{ S String }{s})} {
+		if formatted != Redacted && formatted != Redacted+" "+Redacted+" "+Redacted && formatted != "{S:"+Redacted+"}" {
+			t.Errorf("expected secret to be redacted: %q", formatted)
+		}
+	}
+	if s.Reveal() != "hunter2" {
+		t.Errorf("expected %q got %q", "hunter2", s.Reveal())
+	}
+
+	// Set like any string.
+	var injected String
+	if _, err := literal.Injector.Inject(reflect.ValueOf(&injected).Elem(), "value"); err != nil {
+		t.Fatal(err)
+	}
+	if injected.Reveal() != "value" {
+		t.Errorf("expected %q got %q", "value", injected.Reveal())
+	}
+}
+
+func TestSecret(t *testing.T) {
+	var s Secret[int]
+	if _, err := literal.Injector.Inject(reflect.ValueOf(&s).Elem(), "1234"); err != nil {
+		t.Fatal(err)
+	}
+	if s.Reveal() != 1234 {
+		t.Errorf("expected 1234 got %d", s.Reveal())
+	}
+	if formatted := fmt.Sprintf("%v %+v", s, struct{ S Secret[int] }{s}); formatted != Redacted+" {S:"+Redacted+"}" {
+		t.Errorf("expected secret to be redacted: %q", formatted)
+	}
+	if marshalled, err := json.Marshal(struct{ S Secret[int] }{s}); err != nil {
+		t.Fatal(err)
+	} else if string(marshalled) != `{"S":"`+Redacted+`"}` {
+		t.Errorf("expected secret to be redacted: %s", marshalled)
+	}
+	if _, err := literal.Injector.Inject(reflect.ValueOf(&s).Elem(), "x"); err == nil {
+		t.Error("expected error parsing secret")
+	}
+}
@@ -2,6 +2,7 @@
 package tags
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -15,15 +16,42 @@ type Handler func(k, v string) (bool, error)
 
 // ForEach parses tag and iterates over the key/value pairs, passing each to handler.
 // Iteration may be terminated early if handler returns (true, nil).
+// Iteration stops quietly at malformed key/value pairs. See ForEachStrict.
 // Derived from reflect/type.go Get
 func (tag StructTag) ForEach(handler Handler) error {
+	return tag.forEach(handler, false)
+}
+
+// ForEachStrict is like ForEach, but returns a *TagSyntaxError for malformed key/value pairs, which are only detected
+// once iteration reaches them.
+func (tag StructTag) ForEachStrict(handler Handler) error {
+	return tag.forEach(handler, true)
+}
+
+// Check returns a *TagSyntaxError if tag is malformed, or nil.
+func (tag StructTag) Check() error {
+	return tag.ForEachStrict(Handler(func(k, v string) (bool, error) {
+		return false, nil
+	}))
+}
+
+// forEach implements ForEach, and ForEachStrict if strict.
+func (tag StructTag) forEach(handler Handler, strict bool) error {
+	// The offset of the remaining tag in the original.
+	offset := 0
+	syntaxError := func(i int, msg string) error {
+		if !strict {
+			return nil
+		}
+		return &TagSyntaxError{Offset: offset + i, Msg: msg}
+	}
 	for tag != "" {
 		// skip leading space
 		i := 0
 		for i < len(tag) && tag[i] == ' ' {
 			i++
 		}
-		tag = tag[i:]
+		tag, offset = tag[i:], offset+i
 		if tag == "" {
 			break
 		}
@@ -34,11 +62,17 @@ func (tag StructTag) ForEach(handler Handler) error {
 		for i < len(tag) && tag[i] != ' ' && tag[i] != ':' && tag[i] != '"' {
 			i++
 		}
-		if i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
-			break
+		if i == 0 {
+			return syntaxError(0, "missing key")
+		}
+		if i >= len(tag) || tag[i] != ':' {
+			return syntaxError(i, "expected ':' after key")
+		}
+		if i+1 >= len(tag) || tag[i+1] != '"' {
+			return syntaxError(i+1, "expected '\"' after ':'")
 		}
 		name := string(tag[:i])
-		tag = tag[i+1:]
+		tag, offset = tag[i+1:], offset+i+1
 
 		// scan quoted string to find value
 		i = 1
@@ -49,12 +83,15 @@ func (tag StructTag) ForEach(handler Handler) error {
 			i++
 		}
 		if i >= len(tag) {
-			break
+			return syntaxError(0, "missing closing '\"'")
 		}
 		qvalue := string(tag[:i+1])
-		tag = tag[i+1:]
 
-		value, _ := strconv.Unquote(qvalue)
+		value, err := strconv.Unquote(qvalue)
+		if err != nil && strict {
+			return syntaxError(0, "invalid quoted value "+qvalue)
+		}
+		tag, offset = tag[i+1:], offset+i+1
 
 		if handled, err := handler(name, value); err != nil {
 			return err
@@ -68,6 +105,23 @@ func (tag StructTag) ForEach(handler Handler) error {
 	return nil
 }
 
+// A TagSyntaxError indicates a malformed struct tag.
+type TagSyntaxError struct {
+	// The byte offset of the error in the tag.
+	Offset int
+	// The name of the struct field with the tag, if known, e.g. main.ServerModule.Port.
+	Field string
+	// Describes the error.
+	Msg string
+}
+
+func (e *TagSyntaxError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("syntax error in tag of field %s at offset %d: %s", e.Field, e.Offset, e.Msg)
+	}
+	return fmt.Sprintf("syntax error in tag at offset %d: %s", e.Offset, e.Msg)
+}
+
 // Get returns the value associated with key in the tag string.
 // If there is no such key in the tag, Get returns ("", false).
 // Similar to reflect/type.go Get, but distinguishes between empty tag values and missing tag keys.
@@ -53,3 +53,42 @@ func TestGet(t *testing.T) {
 		}
 	}
 }
+
+func TestForEachStrict(t *testing.T) {
+	for _, testCase := range []struct {
+		tag    StructTag
+		offset int
+	}{
+		{`key:"value" other:"value`, 18},
+		{`key:"value" other :"value"`, 17},
+		{`key:"value" other:value`, 18},
+		{`key:"value" other`, 17},
+		{`key:"value" :"value"`, 12},
+		{`key:"value" other:"\q"`, 18},
+	} {
+		var keys []string
+		err := testCase.tag.ForEachStrict(func(k, v string) (bool, error) {
+			keys = append(keys, k)
+			return false, nil
+		})
+		syntaxErr, ok := err.(*TagSyntaxError)
+		if !ok {
+			t.Errorf("%s: expected *TagSyntaxError got %v", testCase.tag, err)
+			continue
+		}
+		if syntaxErr.Offset != testCase.offset {
+			t.Errorf("%s: expected offset %d got %d", testCase.tag, testCase.offset, syntaxErr.Offset)
+		}
+		if len(keys) != 1 || keys[0] != "key" {
+			t.Errorf("%s: expected to handle key before error but got %v", testCase.tag, keys)
+		}
+		// ForEach stops quietly.
+		if err := testCase.tag.ForEach(func(k, v string) (bool, error) { return false, nil }); err != nil {
+			t.Errorf("%s: unexpected error: %s", testCase.tag, err)
+		}
+	}
+
+	if err := StructTag(` key:"value"  other:"a \"quoted\" value" `).Check(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,168 @@
+// Package validate checks values against validation rules, as given in 'validate' struct tags.
+//
+// Rules are comma separated, e.g. validate:"min=1,max=65535". Supported rules include:
+//   - min=N, max=N: bounds for numbers, or for the length of strings, slices, arrays, maps and chans
+//   - nonempty: a non-zero length for strings, slices, arrays, maps and chans, or otherwise a non-zero value
+//   - oneof=a|b|c: the formatted value must be one of the '|' separated options
+//   - url: an absolute URL string, with a scheme and host
+//
+// Violation messages do not include values, so that secrets are not exposed.
+package validate
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// A Violation describes a value which does not satisfy a rule.
+type Violation struct {
+	// The rule, e.g. "min=1".
+	Rule string
+	msg  string
+}
+
+func (v *Violation) Error() string {
+	return v.msg + " (" + v.Rule + ")"
+}
+
+// Validate checks value against the comma separated rules, returning all violations. Malformed or unknown rules are
+// returned as errors as well.
+func Validate(value reflect.Value, rules string) []error {
+	errs := make([]error, 0)
+	for _, rule := range strings.Split(rules, ",") {
+		if rule == "" {
+			continue
+		}
+		name, arg := rule, ""
+		if i := strings.Index(rule, "="); i >= 0 {
+			name, arg = rule[:i], rule[i+1:]
+		}
+		check, ok := checks[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown validation rule: %q", rule))
+			continue
+		}
+		if msg, err := check(value, arg); err != nil {
+			errs = append(errs, fmt.Errorf("malformed validation rule %q: %s", rule, err))
+		} else if msg != "" {
+			errs = append(errs, &Violation{rule, msg})
+		}
+	}
+	return errs
+}
+
+// A check returns a violation message if value does not satisfy a rule with the given argument, or an error if the
+// rule is malformed.
+type check func(value reflect.Value, arg string) (string, error)
+
+var checks = map[string]check{
+	"min": func(value reflect.Value, arg string) (string, error) {
+		return bound(value, arg, func(c int) bool { return c >= 0 }, "at least")
+	},
+	"max": func(value reflect.Value, arg string) (string, error) {
+		return bound(value, arg, func(c int) bool { return c <= 0 }, "at most")
+	},
+	"nonempty": func(value reflect.Value, _ string) (string, error) {
+		if hasLen(value) {
+			if value.Len() == 0 {
+				return "must not be empty", nil
+			}
+		} else if value.IsZero() {
+			return "must not be zero", nil
+		}
+		return "", nil
+	},
+	"oneof": func(value reflect.Value, arg string) (string, error) {
+		if arg == "" {
+			return "", fmt.Errorf("no options")
+		}
+		s := fmt.Sprint(value.Interface())
+		for _, option := range strings.Split(arg, "|") {
+			if s == option {
+				return "", nil
+			}
+		}
+		return "must be one of " + arg, nil
+	},
+	"url": func(value reflect.Value, _ string) (string, error) {
+		if value.Kind() != reflect.String {
+			return "", fmt.Errorf("not applicable to %s", value.Type())
+		}
+		if u, err := url.Parse(value.String()); err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be an absolute URL", nil
+		}
+		return "", nil
+	},
+}
+
+// bound compares value, or its length, with arg. Returns a violation message unless ok returns true for the result
+// of the comparison (-1, 0 or 1).
+func bound(value reflect.Value, arg string, ok func(int) bool, desc string) (string, error) {
+	var c int
+	switch {
+	case hasLen(value):
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return "", err
+		}
+		if c = compareInt(int64(value.Len()), int64(n)); !ok(c) {
+			return fmt.Sprintf("length must be %s %d", desc, n), nil
+		}
+		return "", nil
+	case value.Kind() >= reflect.Int && value.Kind() <= reflect.Int64:
+		n, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return "", err
+		}
+		c = compareInt(value.Int(), n)
+	case value.Kind() >= reflect.Uint && value.Kind() <= reflect.Uintptr:
+		n, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case value.Uint() < n:
+			c = -1
+		case value.Uint() > n:
+			c = 1
+		}
+	case value.Kind() == reflect.Float32 || value.Kind() == reflect.Float64:
+		n, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case value.Float() < n:
+			c = -1
+		case value.Float() > n:
+			c = 1
+		}
+	default:
+		return "", fmt.Errorf("not applicable to %s", value.Type())
+	}
+	if !ok(c) {
+		return fmt.Sprintf("must be %s %s", desc, arg), nil
+	}
+	return "", nil
+}
+
+func compareInt(a, b int64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// hasLen returns true if value's Kind supports Len.
+func hasLen(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return true
+	}
+	return false
+}
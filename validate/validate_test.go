@@ -0,0 +1,65 @@
+package validate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for _, testCase := range []struct {
+		value      interface{}
+		rules      string
+		violations int
+	}{
+		{8080, "min=1,max=65535", 0},
+		{0, "min=1,max=65535", 1},
+		{70000, "min=1,max=65535", 1},
+		{uint16(1), "min=1", 0},
+		{uint(0), "min=1", 1},
+		{0.5, "min=0.1,max=1", 0},
+		{1.5, "min=0.1,max=1", 1},
+		{"abc", "min=1,max=2", 1},
+		{[]int{1, 2}, "min=1,max=2", 0},
+		{"", "nonempty", 1},
+		{"host", "nonempty", 0},
+		{[]string{}, "nonempty", 1},
+		{0, "nonempty", 1},
+		{"info", "oneof=debug|info|warn", 0},
+		{"error", "oneof=debug|info|warn", 1},
+		{2, "oneof=1|2", 0},
+		{"http://localhost:8080/path", "url", 0},
+		{"localhost:8080", "url", 1},
+		{"/path", "url", 1},
+		{"", "nonempty,url", 2},
+	} {
+		errs := Validate(reflect.ValueOf(testCase.value), testCase.rules)
+		if len(errs) != testCase.violations {
+			t.Errorf("%v %q: expected %d violations but got %v", testCase.value, testCase.rules, testCase.violations, errs)
+		}
+		for _, err := range errs {
+			if _, ok := err.(*Violation); !ok {
+				t.Errorf("%v %q: expected Violation but got %v", testCase.value, testCase.rules, err)
+			}
+		}
+	}
+}
+
+func TestValidateMalformed(t *testing.T) {
+	for _, testCase := range []struct {
+		value interface{}
+		rules string
+	}{
+		{1, "unknown"},
+		{1, "min=x"},
+		{true, "min=1"},
+		{1, "url"},
+		{"a", "oneof="},
+	} {
+		errs := Validate(reflect.ValueOf(testCase.value), testCase.rules)
+		if len(errs) != 1 {
+			t.Errorf("%v %q: expected 1 error but got %v", testCase.value, testCase.rules, errs)
+		} else if _, ok := errs[0].(*Violation); ok {
+			t.Errorf("%v %q: expected malformed rule error but got %v", testCase.value, testCase.rules, errs[0])
+		}
+	}
+}